@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// sseKeepAlive is how often an idle event stream sends a comment, so proxies
+// don't close the connection.
+const sseKeepAlive = 30 * time.Second
+
+// API serves the manager's current state as JSON, using the same service
+// schema as `check --output json`.
+type API struct {
+	manager   *ServiceManager
+	scheduler *Scheduler
+}
+
+func NewAPI(sm *ServiceManager, scheduler *Scheduler) *API {
+	return &API{manager: sm, scheduler: scheduler}
+}
+
+type serviceListResponse struct {
+	Version  int             `json:"version"`
+	Services []serviceReport `json:"services"`
+}
+
+type serviceResponse struct {
+	Version int           `json:"version"`
+	Service serviceReport `json:"service"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (a *API) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/services", a.listServices)
+	mux.HandleFunc("GET /api/services/{id}", a.getService)
+	mux.HandleFunc("POST /api/services/{id}/refresh", a.refreshService)
+	mux.HandleFunc("GET /api/events", a.streamEvents)
+}
+
+func (a *API) listServices(w http.ResponseWriter, r *http.Request) {
+	services := a.manager.List()
+	resp := serviceListResponse{
+		Version:  reportVersion,
+		Services: make([]serviceReport, 0, len(services)),
+	}
+	for _, svc := range services {
+		resp.Services = append(resp.Services, newServiceReport(svc))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (a *API) getService(w http.ResponseWriter, r *http.Request) {
+	svc, ok := a.manager.Get(r.PathValue("id"))
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "service not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, serviceResponse{Version: reportVersion, Service: newServiceReport(svc)})
+}
+
+// refreshService fetches the service immediately and responds with its new
+// state once the fetch completes.
+func (a *API) refreshService(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, ok := a.manager.Get(id); !ok {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "service not found"})
+		return
+	}
+
+	if err := a.scheduler.Refresh(r.Context(), id); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+
+	svc, ok := a.manager.Get(id)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "service not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, serviceResponse{Version: reportVersion, Service: newServiceReport(svc)})
+}
+
+// streamEvents sends status transitions as Server-Sent Events until the client
+// disconnects. ?service=ID limits the stream to one service, plus the offline
+// and online events that concern every service.
+func (a *API) streamEvents(w http.ResponseWriter, r *http.Request) {
+	serviceID := r.URL.Query().Get("service")
+	if serviceID != "" {
+		if _, ok := a.manager.Get(serviceID); !ok {
+			writeJSON(w, http.StatusNotFound, errorResponse{Error: "service not found"})
+			return
+		}
+	}
+
+	events, unsubscribe := a.manager.Events().Subscribe(64)
+	defer unsubscribe()
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case event := <-events:
+			if serviceID != "" && event.ServiceID != "" && event.ServiceID != serviceID {
+				continue
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
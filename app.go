@@ -62,28 +62,36 @@ var (
 type tickMsg time.Time
 
 type refreshMsg struct {
-	Index int
+	ID          string
 	fetchResult *fetch.Result
-	Err   error
+	Err         error
 }
 
+type hookResultMsg HookResult
+
+// maxEventLog is how many on_change results the event log keeps.
+const maxEventLog = 100
+
 type keyMap struct {
-	Up        key.Binding
-	Down      key.Binding
-	Add       key.Binding
-	Edit      key.Binding
-	Delete    key.Binding
-	Open      key.Binding
-	Refresh     key.Binding
-	RefreshAll  key.Binding
-	Help        key.Binding
-	Quit        key.Binding
-	Enter       key.Binding
-	Escape    key.Binding
-	Home      key.Binding
-	End       key.Binding
-	Tab       key.Binding
-	ShiftTab  key.Binding
+	Up         key.Binding
+	Down       key.Binding
+	Add        key.Binding
+	Edit       key.Binding
+	Delete     key.Binding
+	Open       key.Binding
+	Components key.Binding
+	EventLog   key.Binding
+	Refresh    key.Binding
+	RefreshAll key.Binding
+	Redetect   key.Binding
+	Help       key.Binding
+	Quit       key.Binding
+	Enter      key.Binding
+	Escape     key.Binding
+	Home       key.Binding
+	End        key.Binding
+	Tab        key.Binding
+	ShiftTab   key.Binding
 }
 
 var keys = keyMap{
@@ -111,6 +119,14 @@ var keys = keyMap{
 		key.WithKeys("o"),
 		key.WithHelp("o", "open in browser"),
 	),
+	Components: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "expand/collapse components"),
+	),
+	EventLog: key.NewBinding(
+		key.WithKeys("l"),
+		key.WithHelp("l", "toggle event log"),
+	),
 	Refresh: key.NewBinding(
 		key.WithKeys("enter"),
 		key.WithHelp("enter", "refresh selected"),
@@ -119,6 +135,10 @@ var keys = keyMap{
 		key.WithKeys("r"),
 		key.WithHelp("r", "refresh all"),
 	),
+	Redetect: key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "re-detect provider"),
+	),
 	Help: key.NewBinding(
 		key.WithKeys("?"),
 		key.WithHelp("?", "toggle help"),
@@ -160,27 +180,32 @@ func (k keyMap) ShortHelp() []key.Binding {
 func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Home, k.End},
-		{k.Add, k.Edit, k.Delete, k.Open},
-		{k.Refresh, k.RefreshAll, k.Help, k.Quit},
+		{k.Add, k.Edit, k.Delete, k.Open, k.Components, k.EventLog},
+		{k.Refresh, k.RefreshAll, k.Redetect, k.Help, k.Quit},
 	}
 }
 
 type Model struct {
-	manager       *ServiceManager
-	fetchClient   *fetch.Client
-	selected      int
-	sortedIndices []int // Maps display position to actual service index
-	mode          InputMode
-	nameInput     textinput.Model
-	urlInput      textinput.Model
-	intervalInput textinput.Model
-	focusedInput  int
-	viewport      viewport.Model
-	help          help.Model
-	statusMsg     string
-	width         int
-	height        int
-	deleteTarget  int
+	manager            *ServiceManager
+	fetchClient        *fetch.Client
+	selected           int
+	sortedIDs          []string // Maps display position to service ID
+	mode               InputMode
+	nameInput          textinput.Model
+	urlInput           textinput.Model
+	intervalInput      textinput.Model
+	componentsInput    textinput.Model
+	focusedInput       int
+	viewport           viewport.Model
+	help               help.Model
+	statusMsg          string
+	width              int
+	height             int
+	deleteTarget       string
+	editTarget         string
+	componentsExpanded bool
+	showEventLog       bool
+	eventLog           []HookResult // newest first
 }
 
 func initialModel(sm *ServiceManager) Model {
@@ -199,29 +224,30 @@ func initialModel(sm *ServiceManager) Model {
 	intervalInput.CharLimit = 6
 	intervalInput.Width = 20
 
+	componentsInput := textinput.New()
+	componentsInput.Placeholder = "all (e.g. Git Operations, Actions, !Copilot)"
+	componentsInput.CharLimit = 500
+	componentsInput.Width = 50
+
 	vp := viewport.New(80, 20)
 
 	return Model{
-		manager:       sm,
-		fetchClient:   fetch.NewClient(),
-		selected:      0,
-		mode:          ModeNormal,
-		nameInput:     nameInput,
-		urlInput:      urlInput,
-		intervalInput: intervalInput,
-		viewport:      vp,
-		help:          help.New(),
+		manager:         sm,
+		fetchClient:     newFetchClient(sm.Settings()),
+		selected:        0,
+		mode:            ModeNormal,
+		nameInput:       nameInput,
+		urlInput:        urlInput,
+		intervalInput:   intervalInput,
+		componentsInput: componentsInput,
+		viewport:        vp,
+		help:            help.New(),
 	}
 }
 
 func (m Model) Init() tea.Cmd {
-	// Initialize sorted indices
-	services := m.manager.List()
-	m.sortedIndices = make([]int, len(services))
-	for i := range services {
-		m.sortedIndices[i] = i
-	}
-	m.updateSortedIndices()
+	// Initialize sorted IDs
+	m.updateSortedIDs()
 	
 	return tea.Batch(
 		tea.Tick(time.Second, func(t time.Time) tea.Msg {
@@ -260,12 +286,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tickMsg:
-		now := time.Now()
-		services := m.manager.List()
-		for i, svc := range services {
-			if !svc.InFlight && now.After(svc.NextRefreshAt) {
-				cmds = append(cmds, m.refreshServiceCmd(i))
-			}
+		for _, id := range m.manager.Due(time.Now()) {
+			cmds = append(cmds, m.refreshServiceCmd(id))
 		}
 		cmds = append(cmds, tea.Tick(time.Second, func(t time.Time) tea.Msg {
 			return tickMsg(t)
@@ -276,20 +298,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.statusMsg = string(msg)
 		return m, nil
 
-	case refreshMsg:
-		if msg.Err != nil {
-			m.manager.UpdateStatus(msg.Index, StatusConnectionError, nil, nil, "", msg.Err.Error())
-		} else {
-			incidents := convertIncidents(msg.fetchResult.Incidents)
-			maintenances := convertMaintenances(msg.fetchResult.Maintenances)
-			level := convertStatusLevel(msg.fetchResult.Level)
-			m.manager.UpdateStatus(msg.Index, level, incidents, maintenances, msg.fetchResult.ParseNote, "")
+	case hookResultMsg:
+		result := HookResult(msg)
+		m.eventLog = append([]HookResult{result}, m.eventLog...)
+		if len(m.eventLog) > maxEventLog {
+			m.eventLog = m.eventLog[:maxEventLog]
+		}
+		if result.Err != nil {
+			m.statusMsg = fmt.Sprintf("on_change for %s failed (l for log)", result.Event.ServiceName)
 		}
-		m.manager.Save()
-		m.updateSortedIndices()
 		m.viewport.SetContent(m.renderDetails())
 		return m, nil
 
+	case refreshMsg:
+		prev, _ := m.manager.Get(msg.ID)
+		wasOffline := m.manager.Offline()
+		m.manager.ApplyResult(msg.ID, msg.fetchResult, msg.Err)
+		m.manager.Save()
+		m.updateSortedIDs()
+		m.viewport.SetContent(m.renderDetails())
+		return m, m.notifyCmd(msg.ID, prev, wasOffline)
+
 	case tea.KeyMsg:
 		if m.mode == ModeHelp {
 			if msg.String() == "?" || msg.String() == "esc" || msg.String() == "q" {
@@ -314,7 +343,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.viewport.SetContent(m.renderDetails())
 
 		case key.Matches(msg, keys.Down):
-			if m.selected < len(m.sortedIndices)-1 {
+			if m.selected < len(m.sortedIDs)-1 {
 				m.selected++
 			}
 			m.viewport.SetContent(m.renderDetails())
@@ -324,8 +353,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.viewport.SetContent(m.renderDetails())
 
 		case key.Matches(msg, keys.End):
-			if len(m.sortedIndices) > 0 {
-				m.selected = len(m.sortedIndices) - 1
+			if len(m.sortedIDs) > 0 {
+				m.selected = len(m.sortedIDs) - 1
 			}
 			m.viewport.SetContent(m.renderDetails())
 
@@ -335,60 +364,67 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.nameInput.SetValue("")
 			m.urlInput.SetValue("")
 			m.intervalInput.SetValue(fmt.Sprintf("%d", m.manager.GetDefaultInterval()))
+			m.componentsInput.SetValue("")
 			m.nameInput.Focus()
 			m.urlInput.Blur()
 			m.intervalInput.Blur()
+			m.componentsInput.Blur()
 
 		case key.Matches(msg, keys.Edit):
-			if m.selected < len(m.sortedIndices) {
-				actualIndex := m.sortedIndices[m.selected]
-				services := m.manager.List()
-				if actualIndex < len(services) {
-					m.mode = ModeEdit
-					m.focusedInput = 0
-					svc := services[actualIndex]
-					m.nameInput.SetValue(svc.Config.Name)
-					m.urlInput.SetValue(svc.Config.URL)
-					m.intervalInput.SetValue(fmt.Sprintf("%d", svc.Config.RefreshIntervalSeconds))
-					m.nameInput.Focus()
-					m.urlInput.Blur()
-					m.intervalInput.Blur()
-				}
+			if svc, ok := m.selectedService(); ok {
+				m.mode = ModeEdit
+				m.editTarget = svc.Config.ID
+				m.focusedInput = 0
+				m.nameInput.SetValue(svc.Config.Name)
+				m.urlInput.SetValue(svc.Config.URL)
+				m.intervalInput.SetValue(fmt.Sprintf("%d", svc.Config.RefreshIntervalSeconds))
+				m.componentsInput.SetValue(svc.Config.Components.String())
+				m.nameInput.Focus()
+				m.urlInput.Blur()
+				m.intervalInput.Blur()
+				m.componentsInput.Blur()
 			}
 
 		case key.Matches(msg, keys.Delete):
-			if m.selected < len(m.sortedIndices) {
-				actualIndex := m.sortedIndices[m.selected]
-				services := m.manager.List()
-				if actualIndex < len(services) {
-					m.mode = ModeConfirm
-					m.deleteTarget = actualIndex
-				}
+			if svc, ok := m.selectedService(); ok {
+				m.mode = ModeConfirm
+				m.deleteTarget = svc.Config.ID
 			}
 
 		case key.Matches(msg, keys.Open):
-			if m.selected < len(m.sortedIndices) {
-				actualIndex := m.sortedIndices[m.selected]
-				services := m.manager.List()
-				if actualIndex < len(services) {
-					return m, m.openURLCmd(services[actualIndex].Config.URL)
-				}
+			if svc, ok := m.selectedService(); ok {
+				return m, m.openURLCmd(svc.Config.URL)
 			}
 
+		case key.Matches(msg, keys.Components):
+			m.componentsExpanded = !m.componentsExpanded
+			m.viewport.SetContent(m.renderDetails())
+
+		case key.Matches(msg, keys.EventLog):
+			m.showEventLog = !m.showEventLog
+			m.viewport.SetContent(m.renderDetails())
+
 		case key.Matches(msg, keys.Refresh):
-			if m.selected < len(m.sortedIndices) {
-				actualIndex := m.sortedIndices[m.selected]
-				services := m.manager.List()
-				if actualIndex < len(services) {
-					m.statusMsg = fmt.Sprintf("Refreshing %s...", services[actualIndex].Config.Name)
-					return m, m.refreshServiceCmd(actualIndex)
-				}
+			if svc, ok := m.selectedService(); ok {
+				m.statusMsg = fmt.Sprintf("Refreshing %s...", svc.Config.Name)
+				return m, m.refreshServiceCmd(svc.Config.ID)
 			}
 
 		case key.Matches(msg, keys.RefreshAll):
 			m.statusMsg = "Refreshing all services..."
 			return m, m.refreshAllCmd()
 
+		case key.Matches(msg, keys.Redetect):
+			if svc, ok := m.selectedService(); ok {
+				if svc.Config.Provider != "" {
+					m.statusMsg = fmt.Sprintf("%s is pinned to the %s provider", svc.Config.Name, svc.Config.Provider)
+					return m, nil
+				}
+				m.manager.ResetDetection(svc.Config.ID)
+				m.statusMsg = fmt.Sprintf("Re-detecting %s...", svc.Config.Name)
+				return m, m.refreshServiceCmd(svc.Config.ID)
+			}
+
 		case key.Matches(msg, keys.Help):
 			m.mode = ModeHelp
 		}
@@ -403,24 +439,17 @@ func (m Model) handleInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.mode == ModeConfirm {
 		switch msg.String() {
 		case "enter":
-			services := m.manager.List()
-			if m.deleteTarget >= 0 && m.deleteTarget < len(services) {
+			if svc, ok := m.manager.Get(m.deleteTarget); ok {
 				// Get the name for confirmation message
-				deletedName := services[m.deleteTarget].Config.Name
+				deletedName := svc.Config.Name
 				
 				// Delete the service
 				m.manager.Remove(m.deleteTarget)
 				m.manager.Save()
 				
-				// Rebuild sorted indices after deletion
-				m.updateSortedIndices()
-				
-				// Adjust selection to stay in bounds
-				if m.selected >= len(m.sortedIndices) && len(m.sortedIndices) > 0 {
-					m.selected = len(m.sortedIndices) - 1
-				} else if len(m.sortedIndices) == 0 {
-					m.selected = 0
-				}
+				// Rebuild sorted IDs after deletion; the cursor stays at the
+				// same position, within bounds
+				m.updateSortedIDs()
 				
 				m.statusMsg = fmt.Sprintf("Deleted: %s", deletedName)
 			}
@@ -434,30 +463,17 @@ func (m Model) handleInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	switch msg.String() {
 	case "enter":
-		if err := m.submitService(); err != nil {
+		if id, err := m.submitService(); err != nil {
 			m.statusMsg = "Error: " + err.Error()
 		} else {
-			var idx int
-			if m.mode == ModeAdd {
-				idx = len(m.manager.List()) - 1
-				// Rebuild sorted indices after adding
-				m.updateSortedIndices()
-				// Find the new service in sorted view
-				for i, sortedIdx := range m.sortedIndices {
-					if sortedIdx == idx {
-						m.selected = i
-						break
-					}
-				}
-			} else {
-				idx = m.sortedIndices[m.selected]
-				// Rebuild sorted indices after editing (status might have changed)
-				m.updateSortedIndices()
-			}
+			// Rebuild sorted IDs (status might have changed) and keep the
+			// saved service selected
+			m.updateSortedIDs()
+			m.selectID(id)
 			m.statusMsg = "Service saved"
 			m.mode = ModeNormal
 			m.viewport.SetContent(m.renderDetails())
-			return m, m.refreshServiceCmd(idx)
+			return m, m.refreshServiceCmd(id)
 		}
 		return m, nil
 
@@ -466,12 +482,12 @@ func (m Model) handleInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "tab":
-		m.focusedInput = (m.focusedInput + 1) % 3
+		m.focusedInput = (m.focusedInput + 1) % 4
 		m.updateInputFocus()
 		return m, nil
 
 	case "shift+tab":
-		m.focusedInput = (m.focusedInput + 2) % 3
+		m.focusedInput = (m.focusedInput + 3) % 4
 		m.updateInputFocus()
 		return m, nil
 	}
@@ -483,6 +499,8 @@ func (m Model) handleInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.urlInput, cmd = m.urlInput.Update(msg)
 	case 2:
 		m.intervalInput, cmd = m.intervalInput.Update(msg)
+	case 3:
+		m.componentsInput, cmd = m.componentsInput.Update(msg)
 	}
 
 	return m, cmd
@@ -492,6 +510,7 @@ func (m *Model) updateInputFocus() {
 	m.nameInput.Blur()
 	m.urlInput.Blur()
 	m.intervalInput.Blur()
+	m.componentsInput.Blur()
 
 	switch m.focusedInput {
 	case 0:
@@ -500,54 +519,67 @@ func (m *Model) updateInputFocus() {
 		m.urlInput.Focus()
 	case 2:
 		m.intervalInput.Focus()
+	case 3:
+		m.componentsInput.Focus()
 	}
 }
 
-func (m *Model) submitService() error {
+// submitService validates the form and saves it, returning the service's ID.
+func (m *Model) submitService() (string, error) {
 	name := strings.TrimSpace(m.nameInput.Value())
 	urlStr := strings.TrimSpace(m.urlInput.Value())
 	intervalStr := strings.TrimSpace(m.intervalInput.Value())
 
 	if name == "" {
-		return fmt.Errorf("name cannot be empty")
+		return "", fmt.Errorf("name cannot be empty")
 	}
 
 	if urlStr == "" {
-		return fmt.Errorf("URL cannot be empty")
+		return "", fmt.Errorf("URL cannot be empty")
 	}
 
 	if _, err := url.Parse(urlStr); err != nil {
-		return fmt.Errorf("invalid URL: %w", err)
+		return "", fmt.Errorf("invalid URL: %w", err)
 	}
 
 	if !strings.HasPrefix(urlStr, "http://") && !strings.HasPrefix(urlStr, "https://") {
-		return fmt.Errorf("URL must start with http:// or https://")
+		return "", fmt.Errorf("URL must start with http:// or https://")
 	}
 
 	interval, err := strconv.Atoi(intervalStr)
 	if err != nil {
-		return fmt.Errorf("invalid interval: must be a number")
+		return "", fmt.Errorf("invalid interval: must be a number")
 	}
 
 	if interval < 5 || interval > 86400 {
-		return fmt.Errorf("interval must be between 5 and 86400 seconds")
+		return "", fmt.Errorf("interval must be between 5 and 86400 seconds")
 	}
 
 	cfg := ServiceConfig{
 		Name:                   name,
 		URL:                    urlStr,
 		RefreshIntervalSeconds: interval,
+		Components:             ParseComponentFilter(m.componentsInput.Value()),
 	}
 
 	if m.mode == ModeAdd {
 		return m.manager.Add(cfg)
 	} else if m.mode == ModeEdit {
-		// Use actual index, not sorted index
-		actualIdx := m.sortedIndices[m.selected]
-		return m.manager.Update(actualIdx, cfg)
+		// Resolved by ID: the list may have been re-sorted since e was pressed
+		svc, ok := m.manager.Get(m.editTarget)
+		if !ok {
+			return "", fmt.Errorf("service no longer exists")
+		}
+		// Keep settings the form doesn't expose, such as a pinned provider
+		existing := svc.Config
+		existing.Name = cfg.Name
+		existing.URL = cfg.URL
+		existing.RefreshIntervalSeconds = cfg.RefreshIntervalSeconds
+		existing.Components = cfg.Components
+		return existing.ID, m.manager.Update(existing.ID, existing)
 	}
 
-	return nil
+	return "", nil
 }
 
 func (m Model) View() string {
@@ -562,6 +594,15 @@ func (m Model) View() string {
 	commandContent := m.renderCommandWindow()
 	statusBar := m.renderStatusBar(services)
 
+	if m.manager.Offline() {
+		banner := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FAFAFA")).
+			Background(lipgloss.Color(StatusConnectionError.Color())).
+			Padding(0, 1).
+			Render("🔌 Can't reach any status pages. Check your network or VPN; vendor statuses are unknown, not down.")
+		commandContent = lipgloss.JoinVertical(lipgloss.Left, banner, commandContent)
+	}
+
 	var mainContent string
 	if m.height < 20 {
 		mainContent = lipgloss.NewStyle().
@@ -612,21 +653,26 @@ func (m *Model) renderServiceList(services []ServiceState) string {
 		listWidth = 30
 	}
 
-	// Use existing sorted indices to render in the right order
+	byID := make(map[string]ServiceState, len(services))
+	for _, svc := range services {
+		byID[svc.Config.ID] = svc
+	}
+
+	// Use existing sorted IDs to render in the right order
 	var lines []string
 	var addedOperationalSeparator bool
 	var addedMaintenanceSeparator bool
 	var addedDegradedSeparator bool
 	var addedCriticalSeparator bool
-	for i, origIdx := range m.sortedIndices {
-		if origIdx >= len(services) {
+	var addedMonitoringSeparator bool
+	for i, id := range m.sortedIDs {
+		svc, ok := byID[id]
+		if !ok {
 			continue
 		}
-		svc := services[origIdx]
 		
 		// Add separator before first major disruption/critical service
-		if !addedCriticalSeparator && (svc.StatusLevel == StatusMajorDisruption || 
-			svc.StatusLevel == StatusConnectionError || svc.StatusLevel == StatusParseError) {
+		if !addedCriticalSeparator && svc.StatusLevel == StatusMajorDisruption {
 			separator := lipgloss.NewStyle().
 				Foreground(lipgloss.Color("#FF5F87")). // Red for critical
 				Render("🚨 Critical Issues " + strings.Repeat("─", listWidth-20))
@@ -649,6 +695,18 @@ func (m *Model) renderServiceList(services []ServiceState) string {
 			addedDegradedSeparator = true
 		}
 		
+		// Add separator before first service lazystatus couldn't check
+		if !addedMonitoringSeparator && svc.StatusLevel.IsMonitoringProblem() {
+			separator := lipgloss.NewStyle().
+				Foreground(lipgloss.Color(StatusConnectionError.Color())). // Purple for monitoring problems
+				Render("🔌 Monitoring Problems " + strings.Repeat("─", listWidth-25))
+			if i > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, separator)
+			addedMonitoringSeparator = true
+		}
+
 		// Add separator before first planned maintenance service
 		if !addedMaintenanceSeparator && svc.StatusLevel == StatusPlannedMaintenance {
 			separator := lipgloss.NewStyle().
@@ -683,6 +741,9 @@ func (m *Model) renderServiceList(services []ServiceState) string {
 		}
 
 		status := svc.StatusLevel.String()
+		if svc.Flapping {
+			status += lipgloss.NewStyle().Foreground(lipgloss.Color("#FFAF5F")).Render(" ↯ flapping")
+		}
 		
 		var countdown string
 		if svc.InFlight {
@@ -695,7 +756,9 @@ func (m *Model) renderServiceList(services []ServiceState) string {
 			countdown = helpStyle.Render(fmt.Sprintf("Next: %ds", int(until.Seconds())))
 		}
 
-		line := fmt.Sprintf("%s %s\n  %s • %s", statusDot, name, status, countdown)
+		strip := renderHeatStrip(m.manager.History().Buckets(svc.Config.ID, heatStripWindow, heatStripBuckets, time.Now()))
+
+		line := fmt.Sprintf("%s %s\n  %s • %s\n  %s %s", statusDot, name, status, countdown, strip, helpStyle.Render("24h"))
 		if i == m.selected {
 			selectorStyle := lipgloss.NewStyle().
 				Border(lipgloss.RoundedBorder()).
@@ -714,19 +777,29 @@ func (m *Model) renderServiceList(services []ServiceState) string {
 	return strings.Join(lines, "\n\n")
 }
 
+const (
+	heatStripWindow  = 24 * time.Hour
+	heatStripBuckets = 24
+)
+
+// renderHeatStrip draws one colored bar per history bucket, oldest first.
+func renderHeatStrip(buckets []StatusLevel) string {
+	var b strings.Builder
+	for _, level := range buckets {
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(level.Color())).Render("▮"))
+	}
+	return b.String()
+}
+
 func (m Model) renderDetails() string {
-	services := m.manager.List()
-	if len(services) == 0 || m.selected >= len(m.sortedIndices) {
-		return helpStyle.Render("No service selected")
+	if m.showEventLog {
+		return m.renderEventLog()
 	}
-	
-	// Get actual service index from sorted position
-	actualIndex := m.sortedIndices[m.selected]
-	if actualIndex >= len(services) {
+
+	svc, ok := m.selectedService()
+	if !ok {
 		return helpStyle.Render("No service selected")
 	}
-
-	svc := services[actualIndex]
 	
 	var lines []string
 	lines = append(lines, lipgloss.NewStyle().Bold(true).Render("📊 Service Details"))
@@ -736,12 +809,21 @@ func (m Model) renderDetails() string {
 	
 	statusColor := lipgloss.NewStyle().Foreground(lipgloss.Color(svc.StatusLevel.Color()))
 	lines = append(lines, fmt.Sprintf("Status: %s", statusColor.Render(svc.StatusLevel.String())))
+	if svc.PendingCount > 0 {
+		pendingColor := lipgloss.NewStyle().Foreground(lipgloss.Color(svc.PendingLevel.Color()))
+		lines = append(lines, fmt.Sprintf("Pending: %s (%d/%d checks)",
+			pendingColor.Render(svc.PendingLevel.String()), svc.PendingCount, m.manager.ConfirmChecks(svc.Config)))
+	}
+	if svc.Flapping {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("#FFAF5F")).Render("↯ Flapping: notifications paused until the status settles"))
+	}
 	
 	if !svc.Config.LastChecked.IsZero() {
 		lines = append(lines, fmt.Sprintf("Last Checked: %s", svc.Config.LastChecked.Format("15:04:05")))
 	}
 	
 	lines = append(lines, fmt.Sprintf("Refresh Interval: %ds", svc.Config.RefreshIntervalSeconds))
+	lines = append(lines, m.renderUptime(svc)...)
 
 	if svc.LastError != "" {
 		lines = append(lines, "")
@@ -767,6 +849,8 @@ func (m Model) renderDetails() string {
 		lines = append(lines, helpStyle.Render("No recent incidents"))
 	}
 
+	lines = append(lines, m.renderComponents(svc)...)
+
 	if len(svc.Maintenances) > 0 {
 		lines = append(lines, "")
 		lines = append(lines, lipgloss.NewStyle().Bold(true).Render("🔧 Scheduled Maintenance:"))
@@ -783,10 +867,126 @@ func (m Model) renderDetails() string {
 		lines = append(lines, "")
 		lines = append(lines, helpStyle.Render("Parse Note: "+svc.ParseNote))
 	}
+	if svc.Config.Provider == "" && svc.Config.Detected != nil {
+		lines = append(lines, helpStyle.Render(fmt.Sprintf("Detected: %s at %s (D to re-detect)",
+			svc.Config.Detected.Provider, svc.Config.Detected.Endpoint)))
+	}
 
 	return strings.Join(lines, "\n")
 }
 
+// renderUptime shows uptime percentages from the status history and how long
+// the service spent in each level over the last week.
+func (m Model) renderUptime(svc ServiceState) []string {
+	history := m.manager.History()
+	now := time.Now()
+
+	windows := []struct {
+		label  string
+		window time.Duration
+	}{
+		{"24h", 24 * time.Hour},
+		{"7d", 7 * 24 * time.Hour},
+		{"30d", 30 * 24 * time.Hour},
+	}
+	var parts []string
+	for _, w := range windows {
+		if uptime, ok := history.Uptime(svc.Config.ID, w.window, now); ok {
+			parts = append(parts, fmt.Sprintf("%s %.2f%%", w.label, uptime*100))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s —", w.label))
+		}
+	}
+
+	lines := []string{"", lipgloss.NewStyle().Bold(true).Render("📈 Uptime:"), "  " + strings.Join(parts, " • ")}
+
+	durations := history.Durations(svc.Config.ID, now.Add(-7*24*time.Hour), now)
+	if len(durations) == 0 {
+		return lines
+	}
+
+	levels := make([]StatusLevel, 0, len(durations))
+	for level := range durations {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		return getStatusPriority(levels[i]) > getStatusPriority(levels[j])
+	})
+
+	lines = append(lines, helpStyle.Render("  Time in status (7d):"))
+	for _, level := range levels {
+		lines = append(lines, renderComponentLine("  ", fmt.Sprintf("%s: %s", level.String(), formatDuration(durations[level])), level))
+	}
+	return lines
+}
+
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+}
+
+// renderComponents draws the service's components as a tree grouped by
+// component group. Collapsed groups show a single line with their worst status.
+func (m Model) renderComponents(svc ServiceState) []string {
+	var groups []string
+	children := make(map[string][]Component)
+	for _, comp := range svc.Components {
+		if _, ok := children[comp.Group]; !ok {
+			groups = append(groups, comp.Group)
+		}
+		children[comp.Group] = append(children[comp.Group], comp)
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+
+	var lines []string
+	lines = append(lines, "")
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Render("🧩 Components:"))
+
+	for _, group := range groups {
+		comps := children[group]
+		if group == "" {
+			for _, comp := range comps {
+				lines = append(lines, renderComponentLine("", comp.Name, comp.Level))
+			}
+			continue
+		}
+
+		worst := comps[0].Level
+		for _, comp := range comps[1:] {
+			if getStatusPriority(comp.Level) < getStatusPriority(worst) {
+				worst = comp.Level
+			}
+		}
+
+		if !m.componentsExpanded {
+			lines = append(lines, renderComponentLine("▸ ", fmt.Sprintf("%s (%d)", group, len(comps)), worst))
+			continue
+		}
+
+		lines = append(lines, renderComponentLine("▾ ", group, worst))
+		for _, comp := range comps {
+			lines = append(lines, renderComponentLine("    ", comp.Name, comp.Level))
+		}
+	}
+
+	return lines
+}
+
+func renderComponentLine(prefix, name string, level StatusLevel) string {
+	dot := lipgloss.NewStyle().Foreground(lipgloss.Color(level.Color())).Render("●")
+	return fmt.Sprintf("%s%s %s", prefix, dot, name)
+}
+
 func (m Model) renderCommandWindow() string {
 	cmdWidth := m.width - 12
 	if cmdWidth < 30 {
@@ -800,38 +1000,76 @@ func (m Model) renderCommandWindow() string {
 
 	switch m.mode {
 	case ModeAdd:
-		content := fmt.Sprintf("➕ Add Service\nName: %s\nURL: %s\nInterval (sec): %s",
+		content := fmt.Sprintf("➕ Add Service\nName: %s\nURL: %s\nInterval (sec): %s\nComponents: %s",
 			m.nameInput.View(),
 			m.urlInput.View(),
-			m.intervalInput.View())
+			m.intervalInput.View(),
+			m.componentsInput.View())
 		return style.Render(content)
 
 	case ModeEdit:
-		content := fmt.Sprintf("✏️  Edit Service\nName: %s\nURL: %s\nInterval (sec): %s",
+		content := fmt.Sprintf("✏️  Edit Service\nName: %s\nURL: %s\nInterval (sec): %s\nComponents: %s",
 			m.nameInput.View(),
 			m.urlInput.View(),
-			m.intervalInput.View())
+			m.intervalInput.View(),
+			m.componentsInput.View())
 		return style.Render(content)
 
 	case ModeConfirm:
-		services := m.manager.List()
-		if m.deleteTarget >= 0 && m.deleteTarget < len(services) {
-			content := fmt.Sprintf("⚠️  Delete '%s'? | Enter=Yes / Esc=No", services[m.deleteTarget].Config.Name)
+		if svc, ok := m.manager.Get(m.deleteTarget); ok {
+			content := fmt.Sprintf("⚠️  Delete '%s'? | Enter=Yes / Esc=No", svc.Config.Name)
 			return style.Render(content)
 		}
 
 	default:
-		return style.Render("a: add • e: edit • d: delete • o: open • enter: refresh • r: refresh all • ?: help")
+		return style.Render("a: add • e: edit • d: delete • o: open • c: components • enter: refresh • r: refresh all • ?: help")
 	}
 
 	return ""
 }
 
+// renderEventLog lists recent on_change runs with their output.
+func (m Model) renderEventLog() string {
+	var lines []string
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Render("📜 Event Log"))
+	lines = append(lines, "")
+
+	if len(m.eventLog) == 0 {
+		lines = append(lines, helpStyle.Render("No on_change commands have run yet."))
+		return strings.Join(lines, "\n")
+	}
+
+	okStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(StatusOperational.Color()))
+	failStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(StatusMajorDisruption.Color()))
+	for _, result := range m.eventLog {
+		newStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(result.Event.NewLevel.Color()))
+		lines = append(lines, fmt.Sprintf("%s %s: %s → %s",
+			result.Event.At.Format("15:04:05"),
+			result.Event.ServiceName,
+			result.Event.OldLevel,
+			newStyle.Render(result.Event.NewLevel.String())))
+		lines = append(lines, helpStyle.Render("  $ "+result.Command))
+		if result.Err != nil {
+			lines = append(lines, "  "+failStyle.Render(result.Status()))
+		} else {
+			lines = append(lines, "  "+okStyle.Render(result.Status()))
+		}
+		for _, line := range strings.Split(strings.TrimRight(result.Output, "\n"), "\n") {
+			if line != "" {
+				lines = append(lines, helpStyle.Render("  │ ")+line)
+			}
+		}
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (m Model) renderStatusBar(services []ServiceState) string {
 	total := len(services)
 	operational := 0
 	degraded := 0
 	disrupted := 0
+	unreachable := 0
 	
 	for _, svc := range services {
 		switch svc.StatusLevel {
@@ -839,13 +1077,15 @@ func (m Model) renderStatusBar(services []ServiceState) string {
 			operational++
 		case StatusDegraded:
 			degraded++
-		case StatusMajorDisruption, StatusConnectionError, StatusParseError:
+		case StatusMajorDisruption:
 			disrupted++
+		case StatusConnectionError, StatusParseError:
+			unreachable++
 		}
 	}
 
-	stats := fmt.Sprintf("📊 Total: %d • ✅ Operational: %d • ⚠️  Degraded: %d • 🚨 Issues: %d • 🕒 %s",
-		total, operational, degraded, disrupted, time.Now().Format("15:04:05"))
+	stats := fmt.Sprintf("📊 Total: %d • ✅ Operational: %d • ⚠️  Degraded: %d • 🚨 Issues: %d • 🔌 Unchecked: %d • 🕒 %s",
+		total, operational, degraded, disrupted, unreachable, time.Now().Format("15:04:05"))
 
 	if m.statusMsg != "" {
 		return helpStyle.Render(stats) + " • " + statusMsgStyle.Render(m.statusMsg)
@@ -854,20 +1094,20 @@ func (m Model) renderStatusBar(services []ServiceState) string {
 	return helpStyle.Render(stats)
 }
 
-func (m Model) refreshServiceCmd(index int) tea.Cmd {
+func (m Model) refreshServiceCmd(id string) tea.Cmd {
 	return func() tea.Msg {
-		m.manager.SetInFlight(index, true)
-		services := m.manager.List()
-		if index >= len(services) {
-			return refreshMsg{Index: index, Err: fmt.Errorf("invalid index")}
+		m.manager.SetInFlight(id, true)
+		svc, ok := m.manager.Get(id)
+		if !ok {
+			return refreshMsg{ID: id, Err: fmt.Errorf("service %s not found", id)}
 		}
 
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		result, err := m.fetchClient.Fetch(ctx, services[index].Config.URL)
+		result, err := m.fetchClient.Fetch(ctx, fetchTarget(svc.Config))
 		return refreshMsg{
-			Index:       index,
+			ID:          id,
 			fetchResult: result,
 			Err:         err,
 		}
@@ -877,14 +1117,47 @@ func (m Model) refreshServiceCmd(index int) tea.Cmd {
 func (m Model) refreshAllCmd() tea.Cmd {
 	services := m.manager.List()
 	var cmds []tea.Cmd
-	for i := range services {
-		if !services[i].InFlight {
-			cmds = append(cmds, m.refreshServiceCmd(i))
+	for _, svc := range services {
+		if !svc.InFlight {
+			cmds = append(cmds, m.refreshServiceCmd(svc.Config.ID))
 		}
 	}
 	return tea.Batch(cmds...)
 }
 
+// notifyCmd sends a desktop notification if a refresh changed the service
+// from prev and its notify setting asks for one. Losing connectivity to every
+// page sends a single notification instead of one per service, as does the
+// first page read afterwards.
+func (m Model) notifyCmd(id string, prev ServiceState, wasOffline bool) tea.Cmd {
+	monitoring := m.manager.Notifications().MonitoringProblems != NotifyOff
+	offline := m.manager.Offline()
+
+	var n Notification
+	switch {
+	case offline && !wasOffline && notifiesOffline(monitoring, m.manager.List()):
+		n = offlineNotification()
+	case offline:
+		return nil
+	case wasOffline && notifiesOffline(monitoring, m.manager.List()):
+		n = onlineNotification()
+	default:
+		svc, ok := m.manager.Get(id)
+		if !ok {
+			return nil
+		}
+		if n, ok = notificationFor(prev, svc, monitoring); !ok {
+			return nil
+		}
+	}
+
+	return func() tea.Msg {
+		// Best effort; a missing notifier shouldn't interrupt the TUI
+		sendNotification(n)
+		return nil
+	}
+}
+
 func (m Model) openURLCmd(urlStr string) tea.Cmd {
 	return func() tea.Msg {
 		// Use macOS 'open' command to open URL in default browser
@@ -918,6 +1191,19 @@ func convertStatusLevel(level fetch.StatusLevel) StatusLevel {
 	}
 }
 
+func convertComponents(fetchComponents []fetch.Component) []Component {
+	components := make([]Component, len(fetchComponents))
+	for i, comp := range fetchComponents {
+		components[i] = Component{
+			Name:   comp.Name,
+			Status: comp.Status,
+			Group:  comp.Group,
+			Level:  convertStatusLevel(comp.Level),
+		}
+	}
+	return components
+}
+
 func convertIncidents(fetchIncidents []fetch.Incident) []Incident {
 	incidents := make([]Incident, len(fetchIncidents))
 	for i, inc := range fetchIncidents {
@@ -932,6 +1218,7 @@ func convertIncidents(fetchIncidents []fetch.Incident) []Incident {
 		incidents[i] = Incident{
 			ID:         inc.ID,
 			Title:      inc.Title,
+			URL:        inc.URL,
 			Status:     inc.Status,
 			Impact:     inc.Impact,
 			StartedAt:  inc.StartedAt,
@@ -943,40 +1230,65 @@ func convertIncidents(fetchIncidents []fetch.Incident) []Incident {
 	return incidents
 }
 
-func (m *Model) updateSortedIndices() {
+// updateSortedIDs re-sorts the list by status. The cursor follows the
+// selected service to its new position rather than staying on the old one.
+func (m *Model) updateSortedIDs() {
+	var selectedID string
+	if m.selected >= 0 && m.selected < len(m.sortedIDs) {
+		selectedID = m.sortedIDs[m.selected]
+	}
+	defer m.selectID(selectedID)
+
 	services := m.manager.List()
 	if len(services) == 0 {
-		m.sortedIndices = []int{}
+		m.sortedIDs = []string{}
 		return
 	}
 	
-	type indexedService struct {
-		service ServiceState
-		origIndex int
-	}
-	indexed := make([]indexedService, len(services))
-	for i, svc := range services {
-		indexed[i] = indexedService{service: svc, origIndex: i}
-	}
-	sort.SliceStable(indexed, func(i, j int) bool {
-		return getStatusPriority(indexed[i].service.StatusLevel) < getStatusPriority(indexed[j].service.StatusLevel)
+	sort.SliceStable(services, func(i, j int) bool {
+		return getStatusPriority(services[i].StatusLevel) < getStatusPriority(services[j].StatusLevel)
 	})
 	
-	m.sortedIndices = make([]int, len(indexed))
-	for i, item := range indexed {
-		m.sortedIndices[i] = item.origIndex
+	m.sortedIDs = make([]string, len(services))
+	for i, svc := range services {
+		m.sortedIDs[i] = svc.Config.ID
+	}
+}
+
+// selectID moves the cursor to the service with the given ID. If it is no
+// longer listed, the cursor stays put within the bounds of the list.
+func (m *Model) selectID(id string) {
+	for i, sortedID := range m.sortedIDs {
+		if sortedID == id {
+			m.selected = i
+			return
+		}
+	}
+	if m.selected >= len(m.sortedIDs) {
+		m.selected = len(m.sortedIDs) - 1
+	}
+	if m.selected < 0 {
+		m.selected = 0
 	}
 }
 
+// selectedService returns the service at the selected display position.
+func (m Model) selectedService() (ServiceState, bool) {
+	if m.selected < 0 || m.selected >= len(m.sortedIDs) {
+		return ServiceState{}, false
+	}
+	return m.manager.Get(m.sortedIDs[m.selected])
+}
+
 func getStatusPriority(level StatusLevel) int {
 	switch level {
 	case StatusMajorDisruption:
 		return 0
-	case StatusConnectionError:
+	case StatusDegraded:
 		return 1
-	case StatusParseError:
+	case StatusConnectionError:
 		return 2
-	case StatusDegraded:
+	case StatusParseError:
 		return 3
 	case StatusPlannedMaintenance:
 		return 4
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Nagios plugin exit codes.
+const (
+	exitOK       = 0
+	exitWarning  = 1
+	exitCritical = 2
+	exitUnknown  = 3
+)
+
+var exitLabels = map[int]string{
+	exitOK:       "OK",
+	exitWarning:  "WARNING",
+	exitCritical: "CRITICAL",
+	exitUnknown:  "UNKNOWN",
+}
+
+// exitCode maps a status level onto a Nagios plugin state. Maintenance is
+// expected downtime, so it is OK; anything we couldn't determine is UNKNOWN.
+func exitCode(level StatusLevel) int {
+	switch level {
+	case StatusOperational, StatusPlannedMaintenance:
+		return exitOK
+	case StatusDegraded:
+		return exitWarning
+	case StatusMajorDisruption:
+		return exitCritical
+	default:
+		return exitUnknown
+	}
+}
+
+// worseExit reports whether state a should take precedence over b when
+// summarising several services: CRITICAL, then WARNING, then UNKNOWN, then OK.
+func worseExit(a, b int) bool {
+	rank := map[int]int{exitOK: 0, exitUnknown: 1, exitWarning: 2, exitCritical: 3}
+	return rank[a] > rank[b]
+}
+
+// parseInterspersed parses fs from args like fs.Parse, but also reads flags
+// that come after positional arguments, so `check GitHub --timeout 10s` works. The
+// positional arguments are returned in order; anything after "--" is
+// positional even if it looks like a flag.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// runCheck implements `lazystatus check [name...]`: fetch the selected
+// services once, print a summary and return a Nagios-style exit code.
+func runCheck(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	timeout := fs.Duration("timeout", 30*time.Second, "timeout for each service")
+	output := fs.String("output", outputText, "output format: text, json or ndjson")
+	fs.StringVar(output, "o", outputText, "shorthand for --output")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: lazystatus check [name...] [flags]")
+		fmt.Fprintln(stderr, "")
+		fmt.Fprintln(stderr, "Fetch services once and exit 0/1/2/3 (OK/WARNING/CRITICAL/UNKNOWN).")
+		fmt.Fprintln(stderr, "With no names, every configured service is checked.")
+		fmt.Fprintln(stderr, "")
+		fs.PrintDefaults()
+	}
+	names, err := parseInterspersed(fs, args)
+	if err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUnknown
+	}
+	if err := validOutput(*output); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUnknown
+	}
+
+	sm, err := LoadServiceManager()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error initializing service manager: %v\n", err)
+		return exitUnknown
+	}
+
+	services, err := selectServices(sm.List(), names)
+	if err != nil {
+		if *output == outputText {
+			fmt.Fprintf(stdout, "LAZYSTATUS UNKNOWN - %v\n", err)
+		} else {
+			fmt.Fprintln(stderr, err)
+		}
+		return exitUnknown
+	}
+
+	var onDone func(ServiceState)
+	var stream *ndjsonWriter
+	if *output == outputNDJSON {
+		stream = newNDJSONWriter(stdout)
+		onDone = func(svc ServiceState) { stream.WriteService(svc) }
+	}
+
+	scheduler := NewScheduler(sm, newFetchClient(sm.Settings()))
+	scheduler.Timeout = *timeout
+	checkServices(scheduler, services, onDone)
+
+	var checked []ServiceState
+	for _, svc := range services {
+		if state, ok := sm.Get(svc.Config.ID); ok {
+			checked = append(checked, state)
+		}
+	}
+	overall := overallExit(checked)
+
+	switch *output {
+	case outputJSON:
+		if err := writeJSONReport(stdout, checked, overall); err != nil {
+			fmt.Fprintf(stderr, "Error writing report: %v\n", err)
+			return exitUnknown
+		}
+	case outputNDJSON:
+		stream.WriteSummary(overall)
+	default:
+		printCheckSummary(stdout, checked, overall)
+	}
+	return overall
+}
+
+// selectServices picks services by name, ignoring case. No names selects all.
+func selectServices(services []ServiceState, names []string) ([]ServiceState, error) {
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no services configured")
+	}
+	if len(names) == 0 {
+		return services, nil
+	}
+
+	var selected []ServiceState
+	for _, name := range names {
+		found := false
+		for _, svc := range services {
+			if strings.EqualFold(svc.Config.Name, name) {
+				selected = append(selected, svc)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no service named %q", name)
+		}
+	}
+	return selected, nil
+}
+
+// checkServices refreshes the given services concurrently and waits for all
+// of them to finish. If onDone is set it is called with each service's new
+// state as soon as its fetch completes.
+func checkServices(scheduler *Scheduler, services []ServiceState, onDone func(ServiceState)) {
+	var wg sync.WaitGroup
+	for _, svc := range services {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			scheduler.Refresh(context.Background(), id)
+			if state, ok := scheduler.manager.Get(id); ok && onDone != nil {
+				onDone(state)
+			}
+		}(svc.Config.ID)
+	}
+	wg.Wait()
+}
+
+// overallExit is the worst exit code across services.
+func overallExit(services []ServiceState) int {
+	overall := exitOK
+	for _, svc := range services {
+		if code := exitCode(svc.StatusLevel); worseExit(code, overall) {
+			overall = code
+		}
+	}
+	return overall
+}
+
+func printCheckSummary(w io.Writer, services []ServiceState, overall int) {
+	counts := make(map[int]int)
+	for _, svc := range services {
+		counts[exitCode(svc.StatusLevel)]++
+	}
+
+	var parts []string
+	for _, code := range []int{exitCritical, exitWarning, exitUnknown, exitOK} {
+		if counts[code] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[code], strings.ToLower(exitLabels[code])))
+		}
+	}
+	fmt.Fprintf(w, "LAZYSTATUS %s - %s\n", exitLabels[overall], strings.Join(parts, ", "))
+
+	for _, svc := range services {
+		line := fmt.Sprintf("%-8s %s: %s", exitLabels[exitCode(svc.StatusLevel)], svc.Config.Name, svc.StatusLevel)
+		if svc.LastError != "" {
+			line += " (" + svc.LastError + ")"
+		} else if svc.StatusLevel.IsMonitoringProblem() {
+			line += " (" + svc.ParseNote + ")"
+		}
+		fmt.Fprintln(w, line)
+	}
+}
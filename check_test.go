@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseInterspersed(t *testing.T) {
+	tests := []struct {
+		args    []string
+		names   []string
+		timeout time.Duration
+	}{
+		{[]string{"GitHub", "Cloudflare"}, []string{"GitHub", "Cloudflare"}, 30 * time.Second},
+		{[]string{"-timeout", "5s", "GitHub"}, []string{"GitHub"}, 5 * time.Second},
+		{[]string{"GitHub", "-timeout", "5s"}, []string{"GitHub"}, 5 * time.Second},
+		{[]string{"GitHub", "--timeout=5s", "Cloudflare"}, []string{"GitHub", "Cloudflare"}, 5 * time.Second},
+		{[]string{"GitHub", "--", "-timeout", "5s"}, []string{"GitHub", "-timeout", "5s"}, 30 * time.Second},
+		{nil, nil, 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("check", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		timeout := fs.Duration("timeout", 30*time.Second, "")
+
+		names, err := parseInterspersed(fs, tt.args)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.args, err)
+		}
+		if !reflect.DeepEqual(names, tt.names) || *timeout != tt.timeout {
+			t.Errorf("%q: got names %q and timeout %v, want %q and %v", tt.args, names, *timeout, tt.names, tt.timeout)
+		}
+	}
+}
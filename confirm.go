@@ -0,0 +1,72 @@
+package main
+
+// flapWindow is how many recent checks flap detection looks at.
+const flapWindow = 10
+
+// observe records a check that saw level and reports whether it should
+// become the service's status, i.e. it has been seen on confirm consecutive
+// checks. Every change needs confirming, to or from unknown included; only
+// the first check since startup is taken as is. A service starts flapping at
+// threshold level changes within flapWindow checks and stops once they fall
+// to half that.
+func (s *ServiceState) observe(level StatusLevel, confirm, threshold int) bool {
+	first := len(s.recent) == 0
+	recent := make([]StatusLevel, 0, flapWindow)
+	if len(s.recent) >= flapWindow {
+		recent = append(recent, s.recent[len(s.recent)-flapWindow+1:]...)
+	} else {
+		recent = append(recent, s.recent...)
+	}
+	s.recent = append(recent, level)
+
+	changes := 0
+	for i := 1; i < len(s.recent); i++ {
+		if s.recent[i] != s.recent[i-1] {
+			changes++
+		}
+	}
+	switch {
+	case threshold <= 0:
+		s.Flapping = false
+	case changes >= threshold:
+		s.Flapping = true
+	case changes <= threshold/2:
+		s.Flapping = false
+	}
+
+	// Nothing has been committed yet, so there's nothing to protect
+	if level == s.StatusLevel || first {
+		s.PendingLevel, s.PendingCount = StatusUnknown, 0
+		return true
+	}
+
+	if level == s.PendingLevel && s.PendingCount > 0 {
+		s.PendingCount++
+	} else {
+		s.PendingLevel, s.PendingCount = level, 1
+	}
+	if s.PendingCount >= confirm {
+		s.PendingLevel, s.PendingCount = StatusUnknown, 0
+		return true
+	}
+	return false
+}
+
+// ConfirmChecks returns how many consecutive checks a new level needs for
+// the service.
+func (sm *ServiceManager) ConfirmChecks(cfg ServiceConfig) int {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.confirmChecks(cfg)
+}
+
+// confirmChecks is ConfirmChecks for callers that hold sm.mu.
+func (sm *ServiceManager) confirmChecks(cfg ServiceConfig) int {
+	if cfg.ConfirmChecks > 0 {
+		return cfg.ConfirmChecks
+	}
+	if sm.config.Settings.ConfirmChecks > 0 {
+		return sm.config.Settings.ConfirmChecks
+	}
+	return 1
+}
@@ -0,0 +1,79 @@
+package main
+
+import "testing"
+
+func TestObserveConfirms(t *testing.T) {
+	const (
+		up   = StatusOperational
+		down = StatusMajorDisruption
+		unk  = StatusUnknown
+		conn = StatusConnectionError
+	)
+	tests := []struct {
+		name   string
+		checks []StatusLevel
+		want   []StatusLevel // committed level after each check
+	}{
+		{"first check applies at once", []StatusLevel{down}, []StatusLevel{down}},
+		{"change needs 3 checks", []StatusLevel{up, down, down, down}, []StatusLevel{up, up, up, down}},
+		{"interrupted change starts over", []StatusLevel{up, down, down, conn, down}, []StatusLevel{up, up, up, up, up}},
+		{"unknown needs confirming", []StatusLevel{up, unk, up}, []StatusLevel{up, up, up}},
+		{"change from unknown needs confirming", []StatusLevel{up, unk, unk, unk, down, up}, []StatusLevel{up, up, up, unk, unk, unk}},
+		{"first check of unknown", []StatusLevel{unk, up, up, up}, []StatusLevel{unk, unk, unk, up}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s ServiceState
+			for i, level := range tt.checks {
+				if s.observe(level, 3, 0) {
+					s.StatusLevel = level
+				}
+				if s.StatusLevel != tt.want[i] {
+					t.Fatalf("check %d (%v): got %v, want %v", i+1, level, s.StatusLevel, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateStatusWhilePending(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	sm, err := LoadServiceManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := sm.Add(ServiceConfig{Name: "GitHub", URL: "https://www.githubstatus.com", ConfirmChecks: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, unsubscribe := sm.Events().Subscribe(16)
+	defer unsubscribe()
+
+	sm.UpdateStatus(id, StatusOperational, "All Systems Operational", nil, nil, nil, "", "")
+	incidents := []Incident{{ID: "r5w4mkpd2ns2", Title: "Elevated error rates"}}
+	components := []Component{{Name: "Actions", Level: StatusDegraded}}
+	sm.UpdateStatus(id, StatusDegraded, "Minor Service Outage", incidents, nil, components, "", "")
+
+	svc, _ := sm.Get(id)
+	if svc.StatusLevel != StatusOperational || svc.PendingLevel != StatusDegraded {
+		t.Fatalf("got %v pending %v, want operational pending degraded", svc.StatusLevel, svc.PendingLevel)
+	}
+	if len(svc.Incidents) != 1 || len(svc.Components) != 1 {
+		t.Errorf("got %d incidents and %d components while pending, want 1 and 1", len(svc.Incidents), len(svc.Components))
+	}
+
+	// A failed check keeps what the page last listed
+	sm.UpdateStatus(id, StatusConnectionError, "Connection error", nil, nil, nil, "", "timeout")
+	if svc, _ := sm.Get(id); len(svc.Incidents) != 1 {
+		t.Errorf("got %d incidents after a failed check, want 1", len(svc.Incidents))
+	}
+
+	var types []EventType
+	for len(events) > 0 {
+		types = append(types, (<-events).Type)
+	}
+	if len(types) != 1 || types[0] != EventIncidentOpened {
+		t.Errorf("got events %v, want [incident_opened]", types)
+	}
+}
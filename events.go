@@ -0,0 +1,360 @@
+package main
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventStatusChanged      EventType = "status_changed"
+	EventIncidentOpened     EventType = "incident_opened"
+	EventIncidentResolved   EventType = "incident_resolved"
+	EventMaintenanceStarted EventType = "maintenance_started"
+	EventMaintenanceEnded   EventType = "maintenance_ended"
+	EventFlappingStarted    EventType = "flapping_started"
+	EventFlappingStopped    EventType = "flapping_stopped"
+	// Monitoring events mean the page couldn't be read, not that the
+	// service changed.
+	EventMonitoringFailed    EventType = "monitoring_failed"
+	EventMonitoringRecovered EventType = "monitoring_recovered"
+	// Offline replaces monitoring_failed for every service when all of
+	// them fail at once, and Online is sent when one reads its page again.
+	EventOffline EventType = "offline"
+	EventOnline  EventType = "online"
+)
+
+// Event describes one transition detected by UpdateStatus. OldLevel and
+// NewLevel are set on every service event; Incident or Maintenance is set for
+// the corresponding event types. Offline and online events concern every
+// service, so they leave the service fields empty.
+type Event struct {
+	Type        EventType
+	ServiceID   string
+	ServiceName string
+	URL         string
+	At          time.Time
+	OldLevel    StatusLevel
+	NewLevel    StatusLevel
+	Incident    *Incident
+	Maintenance *Maintenance
+}
+
+func (e Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type        EventType    `json:"type"`
+		ServiceID   string       `json:"service_id"`
+		ServiceName string       `json:"service_name"`
+		URL         string       `json:"url"`
+		At          time.Time    `json:"at"`
+		OldLevel    string       `json:"old_level"`
+		NewLevel    string       `json:"new_level"`
+		Incident    *Incident    `json:"incident,omitempty"`
+		Maintenance *Maintenance `json:"maintenance,omitempty"`
+	}{e.Type, e.ServiceID, e.ServiceName, e.URL, e.At, e.OldLevel.Key(), e.NewLevel.Key(), e.Incident, e.Maintenance})
+}
+
+// EventBus fans events out to subscribers. Publishing never blocks: a
+// subscriber that falls behind its buffer misses events rather than stalling
+// refreshes.
+type EventBus struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{subs: make(map[chan Event]struct{})}
+}
+
+// Subscribe returns a channel of future events and a function that
+// unsubscribes and closes it.
+func (b *EventBus) Subscribe(buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+func (b *EventBus) publish(events []Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subs {
+		for _, event := range events {
+			select {
+			case ch <- event:
+			default:
+			}
+		}
+	}
+}
+
+// statusEvents compares a service's previous state with the result about to
+// be stored and returns the transitions between them. The first check after
+// startup has nothing to compare with, so it is never a transition.
+func statusEvents(prev ServiceState, level StatusLevel, incidents []Incident, maintenances []Maintenance, now time.Time) []Event {
+	if firstCheck(prev) {
+		return nil
+	}
+
+	base := Event{
+		ServiceID:   prev.Config.ID,
+		ServiceName: prev.Config.Name,
+		URL:         prev.Config.URL,
+		At:          now,
+		OldLevel:    prev.StatusLevel,
+		NewLevel:    level,
+	}
+
+	var events []Event
+	switch {
+	case prev.StatusLevel == level:
+	case level.IsMonitoringProblem() && !prev.StatusLevel.IsMonitoringProblem():
+		event := base
+		event.Type = EventMonitoringFailed
+		events = append(events, event)
+	case prev.StatusLevel.IsMonitoringProblem() && !level.IsMonitoringProblem():
+		event := base
+		event.Type = EventMonitoringRecovered
+		events = append(events, event)
+		// Report a change the page made while it couldn't be read
+		if prev.pageLevel != StatusUnknown && prev.pageLevel != level {
+			event := base
+			event.Type = EventStatusChanged
+			event.OldLevel = prev.pageLevel
+			events = append(events, event)
+		}
+	case level.IsMonitoringProblem():
+		// Still failing, just differently
+	default:
+		event := base
+		event.Type = EventStatusChanged
+		events = append(events, event)
+	}
+
+	// Incidents and maintenances are only known when both checks read the
+	// page; otherwise a failed fetch would look like everything resolving
+	if !hasPageData(prev.StatusLevel) || !hasPageData(level) {
+		return events
+	}
+
+	wasOpen := make(map[string]bool)
+	for _, inc := range prev.Incidents {
+		if inc.ResolvedAt == nil {
+			wasOpen[incidentKey(inc)] = true
+		}
+	}
+	for _, inc := range incidents {
+		key := incidentKey(inc)
+		switch {
+		case inc.ResolvedAt == nil && !wasOpen[key]:
+			event := base
+			event.Type = EventIncidentOpened
+			event.Incident = &inc
+			events = append(events, event)
+		case inc.ResolvedAt != nil && wasOpen[key]:
+			event := base
+			event.Type = EventIncidentResolved
+			event.Incident = &inc
+			events = append(events, event)
+		}
+		delete(wasOpen, key)
+	}
+	// Pages drop resolved incidents from their summary, so anything still
+	// in wasOpen has been resolved
+	for _, inc := range prev.Incidents {
+		if key := incidentKey(inc); wasOpen[key] {
+			delete(wasOpen, key)
+			event := base
+			event.Type = EventIncidentResolved
+			event.Incident = &inc
+			events = append(events, event)
+		}
+	}
+
+	wasActive := make(map[string]bool)
+	for _, maint := range prev.Maintenances {
+		if maint.Active(prev.Config.LastChecked) {
+			wasActive[maintenanceKey(maint)] = true
+		}
+	}
+	for _, maint := range maintenances {
+		key := maintenanceKey(maint)
+		active := maint.Active(now)
+		switch {
+		case active && !wasActive[key]:
+			event := base
+			event.Type = EventMaintenanceStarted
+			event.Maintenance = &maint
+			events = append(events, event)
+		case !active && wasActive[key]:
+			event := base
+			event.Type = EventMaintenanceEnded
+			event.Maintenance = &maint
+			events = append(events, event)
+		}
+		delete(wasActive, key)
+	}
+	for _, maint := range prev.Maintenances {
+		if key := maintenanceKey(maint); wasActive[key] {
+			delete(wasActive, key)
+			event := base
+			event.Type = EventMaintenanceEnded
+			event.Maintenance = &maint
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+// flapEvents reports a service starting or stopping flapping. While a service
+// flaps, UpdateStatus publishes nothing else for it.
+func flapEvents(prev, next ServiceState, now time.Time) []Event {
+	if prev.Flapping == next.Flapping {
+		return nil
+	}
+	event := Event{
+		Type:        EventFlappingStarted,
+		ServiceID:   next.Config.ID,
+		ServiceName: next.Config.Name,
+		URL:         next.Config.URL,
+		At:          now,
+		OldLevel:    prev.StatusLevel,
+		NewLevel:    next.StatusLevel,
+	}
+	if !next.Flapping {
+		event.Type = EventFlappingStopped
+	}
+	return []Event{event}
+}
+
+// firstCheck reports whether prev is a service's state before its first check
+// since startup.
+func firstCheck(prev ServiceState) bool {
+	return len(prev.recent) == 0
+}
+
+// routeMonitoring keeps losing our own network from reading as every vendor
+// failing. A service's monitoring_failed is held back until a check reads
+// some page, which shows the network is up; if every service fails instead,
+// the held events are dropped for a single offline event. Services whose
+// failure was dropped don't report recovering from it, and the first page
+// read again sends one online event. level is what the check just saw.
+// Callers hold sm.mu.
+func (sm *ServiceManager) routeMonitoring(id string, level StatusLevel, wasOffline bool, events []Event, now time.Time) []Event {
+	if len(sm.states) < 2 {
+		return events
+	}
+
+	var routed []Event
+	for _, event := range events {
+		switch event.Type {
+		case EventMonitoringFailed:
+			sm.held = append(sm.held, event)
+			continue
+		case EventMonitoringRecovered:
+			if sm.dropHeld(id) || sm.unreported[id] {
+				// Its failure was never reported
+				delete(sm.unreported, id)
+				continue
+			}
+		}
+		routed = append(routed, event)
+	}
+
+	offline := sm.offline()
+	switch {
+	case offline && !wasOffline:
+		for _, event := range sm.held {
+			sm.unreported[event.ServiceID] = true
+		}
+		sm.held = nil
+		routed = append(routed, Event{Type: EventOffline, At: now, NewLevel: StatusConnectionError})
+	case !offline && wasOffline:
+		routed = append(routed, Event{Type: EventOnline, At: now, OldLevel: StatusConnectionError})
+	}
+
+	if !offline && sm.unreported[id] && level.IsMonitoringProblem() {
+		// Still failing now that the network is back, so it is this vendor
+		delete(sm.unreported, id)
+		if state := sm.states[sm.indexOf(id)]; state.StatusLevel.IsMonitoringProblem() {
+			sm.held = append(sm.held, Event{
+				Type:        EventMonitoringFailed,
+				ServiceID:   id,
+				ServiceName: state.Config.Name,
+				URL:         state.Config.URL,
+				At:          now,
+				OldLevel:    state.pageLevel,
+				NewLevel:    state.StatusLevel,
+			})
+		}
+	}
+
+	if hasPageData(level) && !offline {
+		// Only for services still failing; one that since recovered or
+		// started flapping has nothing left to report
+		var failing []Event
+		for _, event := range sm.held {
+			if i := sm.indexOf(event.ServiceID); i >= 0 && sm.states[i].StatusLevel.IsMonitoringProblem() && !sm.states[i].Flapping {
+				failing = append(failing, event)
+			}
+		}
+		routed = append(failing, routed...)
+		sm.held = nil
+	}
+	return routed
+}
+
+// dropHeld discards a held monitoring_failed for id, reporting whether there
+// was one.
+func (sm *ServiceManager) dropHeld(id string) bool {
+	for i, event := range sm.held {
+		if event.ServiceID == id {
+			sm.held = append(sm.held[:i], sm.held[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// hasPageData reports whether a check at this level read the status page.
+func hasPageData(level StatusLevel) bool {
+	switch level {
+	case StatusUnknown, StatusConnectionError, StatusParseError:
+		return false
+	default:
+		return true
+	}
+}
+
+func incidentKey(inc Incident) string {
+	if inc.ID != "" {
+		return inc.ID
+	}
+	return inc.Title
+}
+
+func maintenanceKey(maint Maintenance) string {
+	if maint.ID != "" {
+		return maint.ID
+	}
+	return maint.Title
+}
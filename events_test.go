@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// drain returns the events waiting on ch as "type service" strings.
+func drain(ch <-chan Event, names map[string]string) []string {
+	var got []string
+	for len(ch) > 0 {
+		event := <-ch
+		got = append(got, strings.TrimSpace(fmt.Sprintf("%s %s", event.Type, names[event.ServiceID])))
+	}
+	return got
+}
+
+func TestMonitoringEventsWhenOffline(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	sm, err := LoadServiceManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Flapping has its own events; this is only about routing failures
+	sm.config.Settings.FlapThreshold = 0
+	ids := make(map[string]string)
+	names := make(map[string]string)
+	for _, name := range []string{"A", "B", "C"} {
+		id, err := sm.Add(ServiceConfig{Name: name, URL: "https://" + strings.ToLower(name) + ".example.com"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[name], names[id] = id, name
+	}
+	events, unsubscribe := sm.Events().Subscribe(64)
+	defer unsubscribe()
+
+	up := func(name string) { sm.UpdateStatus(ids[name], StatusOperational, "", nil, nil, nil, "", "") }
+	fail := func(name string) { sm.UpdateStatus(ids[name], StatusConnectionError, "", nil, nil, nil, "", "timeout") }
+
+	steps := []struct {
+		name  string
+		check func()
+		want  []string
+	}{
+		{"first checks", func() { up("A"); up("B"); up("C") }, nil},
+		{"one vendor fails", func() { fail("A") }, nil},
+		{"another page is read", func() { up("B") }, []string{"monitoring_failed A"}},
+		{"vendor recovers", func() { up("A") }, []string{"monitoring_recovered A"}},
+		{"blip", func() { fail("A"); up("A") }, nil},
+		{"network drops", func() { fail("A"); fail("B"); fail("C") }, []string{"offline"}},
+		{"still offline", func() { fail("A") }, nil},
+		{"network returns", func() { up("A") }, []string{"online"}},
+		{"vendor still down", func() { fail("B") }, nil},
+		{"last recovers", func() { up("C") }, []string{"monitoring_failed B"}},
+	}
+
+	for _, step := range steps {
+		step.check()
+		got := drain(events, names)
+		if strings.Join(got, ", ") != strings.Join(step.want, ", ") {
+			t.Errorf("%s: got %v, want %v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestStatusEventsSkipFirstCheck(t *testing.T) {
+	var prev ServiceState
+	if events := statusEvents(prev, StatusConnectionError, nil, nil, testEvent(EventStatusChanged).At); len(events) != 0 {
+		t.Errorf("got %d events for the first check, want 0", len(events))
+	}
+
+	prev.observe(StatusOperational, 1, 0)
+	prev.StatusLevel = StatusOperational
+	events := statusEvents(prev, StatusConnectionError, nil, nil, testEvent(EventStatusChanged).At)
+	if len(events) != 1 || events[0].Type != EventMonitoringFailed {
+		t.Errorf("got %v, want one monitoring_failed", events)
+	}
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	historyRetention     = 30 * 24 * time.Hour
+	historyCompactEvery  = 1000 // appended lines between compactions
+	historyFileName      = "history.jsonl"
+	historyTempExtension = ".tmp"
+)
+
+// HistoryEntry records that a service entered a status level at a given time.
+// The level holds until the service's next entry.
+type HistoryEntry struct {
+	Service string      `json:"service"`
+	Level   StatusLevel `json:"level"`
+	At      time.Time   `json:"at"`
+}
+
+// History is an append-only log of status transitions, one JSON object per
+// line. It is rewritten without expired or redundant entries on open and
+// after every historyCompactEvery appends. A nil *History records nothing.
+type History struct {
+	mu       sync.RWMutex
+	filePath string
+	file     *os.File
+	entries  map[string][]HistoryEntry
+	appended int
+	dirty    bool // entries changed in a way appending can't express
+}
+
+func OpenHistory(dir string) (*History, error) {
+	h := &History{
+		filePath: filepath.Join(dir, historyFileName),
+		entries:  make(map[string][]HistoryEntry),
+	}
+
+	if err := h.load(); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err := h.compact(time.Now()); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (h *History) load() error {
+	f, err := os.Open(h.filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// Skip a torn line from a crash mid-write
+			continue
+		}
+		h.entries[entry.Service] = append(h.entries[entry.Service], entry)
+	}
+
+	for service := range h.entries {
+		entries := h.entries[service]
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].At.Before(entries[j].At)
+		})
+	}
+	return scanner.Err()
+}
+
+// compact drops entries that ended before the retention window and merges
+// repeated levels, then atomically rewrites the file and reopens it for
+// appending.
+func (h *History) compact(now time.Time) error {
+	cutoff := now.Add(-historyRetention)
+
+	for service, entries := range h.entries {
+		var kept []HistoryEntry
+		for i, entry := range entries {
+			if i+1 < len(entries) && !entries[i+1].At.After(cutoff) {
+				continue
+			}
+			if len(kept) > 0 && kept[len(kept)-1].Level == entry.Level {
+				continue
+			}
+			kept = append(kept, entry)
+		}
+		if len(kept) == 0 {
+			delete(h.entries, service)
+			continue
+		}
+		h.entries[service] = kept
+	}
+
+	tmpPath := h.filePath + historyTempExtension
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, entries := range h.entries {
+		for _, entry := range entries {
+			if err := enc.Encode(entry); err != nil {
+				tmp.Close()
+				return err
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if h.file != nil {
+		h.file.Close()
+		h.file = nil
+	}
+	if err := os.Rename(tmpPath, h.filePath); err != nil {
+		return err
+	}
+
+	h.file, err = os.OpenFile(h.filePath, os.O_APPEND|os.O_WRONLY, 0644)
+	h.appended = 0
+	h.dirty = false
+	return err
+}
+
+// Record appends an entry if level differs from the service's current level.
+func (h *History) Record(service string, level StatusLevel, at time.Time) error {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entries := h.entries[service]
+	if len(entries) > 0 && entries[len(entries)-1].Level == level {
+		return nil
+	}
+
+	entry := HistoryEntry{Service: service, Level: level, At: at}
+	h.entries[service] = append(entries, entry)
+
+	if h.file == nil {
+		return nil
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := h.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+
+	h.appended++
+	if h.dirty || h.appended >= historyCompactEvery {
+		return h.compact(at)
+	}
+	return nil
+}
+
+// Rekey moves a service's entries from one key to another.
+func (h *History) Rekey(from, to string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entries, ok := h.entries[from]
+	if !ok || len(h.entries[to]) > 0 {
+		return
+	}
+	for i := range entries {
+		entries[i].Service = to
+	}
+	h.entries[to] = entries
+	delete(h.entries, from)
+	h.dirty = true
+}
+
+// Retain forgets every service not in services, such as ones removed from
+// the config. Compaction keeps each service's last entry however old it is,
+// so without this their history would never leave the file.
+func (h *History) Retain(services []string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	keep := make(map[string]bool, len(services))
+	for _, service := range services {
+		keep[service] = true
+	}
+	for service := range h.entries {
+		if !keep[service] {
+			delete(h.entries, service)
+			h.dirty = true
+		}
+	}
+}
+
+// Durations returns how long the service spent in each level between since
+// and now.
+func (h *History) Durations(service string, since, now time.Time) map[StatusLevel]time.Duration {
+	if h == nil {
+		return nil
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	durations := make(map[StatusLevel]time.Duration)
+	entries := h.entries[service]
+	for i, entry := range entries {
+		start := entry.At
+		end := now
+		if i+1 < len(entries) {
+			end = entries[i+1].At
+		}
+		if start.Before(since) {
+			start = since
+		}
+		if end.After(now) {
+			end = now
+		}
+		if end.After(start) {
+			durations[entry.Level] += end.Sub(start)
+		}
+	}
+	return durations
+}
+
+// Buckets splits the window ending at now into n equal buckets and returns
+// the worst level seen in each. Buckets with no data are StatusUnknown.
+func (h *History) Buckets(service string, window time.Duration, n int, now time.Time) []StatusLevel {
+	if h == nil {
+		return make([]StatusLevel, n)
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	buckets := make([]StatusLevel, n)
+	entries := h.entries[service]
+	start := now.Add(-window)
+	size := window / time.Duration(n)
+
+	for i := range buckets {
+		bucketStart := start.Add(time.Duration(i) * size)
+		bucketEnd := bucketStart.Add(size)
+		for j, entry := range entries {
+			entryEnd := now
+			if j+1 < len(entries) {
+				entryEnd = entries[j+1].At
+			}
+			if !entry.At.Before(bucketEnd) || !entryEnd.After(bucketStart) || entry.Level == StatusUnknown {
+				continue
+			}
+			if buckets[i] == StatusUnknown || getStatusPriority(entry.Level) < getStatusPriority(buckets[i]) {
+				buckets[i] = entry.Level
+			}
+		}
+	}
+	return buckets
+}
+
+// Uptime returns the fraction of the window the service was operational or in
+// planned maintenance. Time spent in an unknown or error state is left out of
+// the calculation; ok is false if nothing is known about the window.
+func (h *History) Uptime(service string, window time.Duration, now time.Time) (uptime float64, ok bool) {
+	var up, known time.Duration
+	for level, d := range h.Durations(service, now.Add(-window), now) {
+		switch level {
+		case StatusOperational, StatusPlannedMaintenance:
+			up += d
+			known += d
+		case StatusDegraded, StatusMajorDisruption:
+			known += d
+		}
+	}
+	if known == 0 {
+		return 0, false
+	}
+	return float64(up) / float64(known), true
+}
+
+func (h *History) Close() error {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.file == nil {
+		return nil
+	}
+	if h.dirty {
+		if err := h.compact(time.Now()); err != nil {
+			return err
+		}
+	}
+	err := h.file.Close()
+	h.file = nil
+	return err
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// readHistoryFile returns the entries in dir's history file, in file order.
+func readHistoryFile(t *testing.T, dir string) []HistoryEntry {
+	t.Helper()
+	f, err := os.Open(filepath.Join(dir, historyFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("bad line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestHistoryCompaction(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now().Truncate(time.Second)
+	old := now.Add(-historyRetention - 48*time.Hour)
+
+	// Written out of order, with a repeated level and a line torn by a crash
+	lines := []HistoryEntry{
+		{Service: "a", Level: StatusOperational, At: old},
+		{Service: "a", Level: StatusMajorDisruption, At: old.Add(time.Hour)},
+		{Service: "a", Level: StatusOperational, At: now.Add(-time.Hour)},
+		{Service: "a", Level: StatusOperational, At: now.Add(-30 * time.Minute)},
+		{Service: "b", Level: StatusDegraded, At: old},
+		{Service: "a", Level: StatusDegraded, At: now.Add(-2 * time.Hour)},
+	}
+	f, err := os.Create(filepath.Join(dir, historyFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := json.NewEncoder(f)
+	for _, line := range lines {
+		enc.Encode(line)
+	}
+	f.WriteString(`{"service":"a","lev`)
+	f.Close()
+
+	h, err := OpenHistory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	// a's first entry ended before the window and is dropped, but the
+	// disruption after it still covers the start of the window. b has
+	// nothing newer, so its last entry is kept.
+	want := map[string][]StatusLevel{
+		"a": {StatusMajorDisruption, StatusDegraded, StatusOperational},
+		"b": {StatusDegraded},
+	}
+	got := make(map[string][]StatusLevel)
+	for _, entry := range readHistoryFile(t, dir) {
+		got[entry.Service] = append(got[entry.Service], entry.Level)
+	}
+	for service, levels := range want {
+		if len(got[service]) != len(levels) {
+			t.Fatalf("%s: got %v, want %v", service, got[service], levels)
+		}
+		for i := range levels {
+			if got[service][i] != levels[i] {
+				t.Errorf("%s: got %v, want %v", service, got[service], levels)
+			}
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("got services %v", got)
+	}
+}
+
+func TestHistoryDurations(t *testing.T) {
+	h, err := OpenHistory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	now := time.Date(2024, 6, 12, 12, 0, 0, 0, time.UTC)
+	h.Record("a", StatusOperational, now.Add(-10*time.Hour))
+	h.Record("a", StatusMajorDisruption, now.Add(-4*time.Hour))
+	h.Record("a", StatusMajorDisruption, now.Add(-3*time.Hour)) // no change
+	h.Record("a", StatusConnectionError, now.Add(-2*time.Hour))
+	h.Record("a", StatusOperational, now.Add(-time.Hour))
+
+	durations := h.Durations("a", now.Add(-8*time.Hour), now)
+	want := map[StatusLevel]time.Duration{
+		StatusOperational:     5 * time.Hour, // clipped to the window
+		StatusMajorDisruption: 2 * time.Hour,
+		StatusConnectionError: time.Hour,
+	}
+	if len(durations) != len(want) {
+		t.Errorf("got %v, want %v", durations, want)
+	}
+	for level, d := range want {
+		if durations[level] != d {
+			t.Errorf("%v: got %v, want %v", level, durations[level], d)
+		}
+	}
+
+	// The hour of connection errors is left out: 5 of 7 known hours were up
+	uptime, ok := h.Uptime("a", 8*time.Hour, now)
+	if !ok || uptime != 5.0/7.0 {
+		t.Errorf("got uptime %v %v, want %v", uptime, ok, 5.0/7.0)
+	}
+	if _, ok := h.Uptime("unknown", 8*time.Hour, now); ok {
+		t.Error("got uptime for a service with no history")
+	}
+}
+
+func TestHistoryBuckets(t *testing.T) {
+	h, err := OpenHistory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	now := time.Date(2024, 6, 12, 12, 0, 0, 0, time.UTC)
+	h.Record("a", StatusOperational, now.Add(-3*time.Hour))
+	h.Record("a", StatusDegraded, now.Add(-150*time.Minute))
+	h.Record("a", StatusOperational, now.Add(-140*time.Minute))
+	h.Record("a", StatusUnknown, now.Add(-90*time.Minute))
+
+	// One bucket per hour over four hours; the worst level wins and
+	// unknown time leaves a bucket empty
+	got := h.Buckets("a", 4*time.Hour, 4, now)
+	want := []StatusLevel{StatusUnknown, StatusDegraded, StatusOperational, StatusUnknown}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestHistoryRetain(t *testing.T) {
+	dir := t.TempDir()
+	h, err := OpenHistory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	h.Record("kept", StatusOperational, now)
+	h.Record("removed", StatusOperational, now)
+	h.Retain([]string{"kept"})
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := readHistoryFile(t, dir)
+	if len(entries) != 1 || entries[0].Service != "kept" {
+		t.Errorf("got %v, want only kept's entry", entries)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHookTimeout = 30 * time.Second
+	// maxHookOutput caps how much of a hook's output is kept for the log.
+	maxHookOutput = 16 * 1024
+)
+
+// HookResult describes one run of an on_change command.
+type HookResult struct {
+	Event    Event
+	Command  string
+	Output   string
+	Err      error
+	Duration time.Duration
+	TimedOut bool
+	Timeout  time.Duration
+}
+
+// HookRunner runs on_change commands when a service's status changes. A
+// service's own command replaces the global one.
+type HookRunner struct {
+	manager *ServiceManager
+	global  string
+	timeout time.Duration
+
+	// OnResult, if set, is called after each command finishes.
+	OnResult func(HookResult)
+
+	wg sync.WaitGroup
+}
+
+func NewHookRunner(sm *ServiceManager) *HookRunner {
+	cfg := sm.Notifications()
+	timeout := defaultHookTimeout
+	if cfg.OnChangeTimeout > 0 {
+		timeout = time.Duration(cfg.OnChangeTimeout) * time.Second
+	}
+	return &HookRunner{manager: sm, global: cfg.OnChange, timeout: timeout}
+}
+
+// Run starts commands for status changes until ctx is cancelled or the
+// channel is closed, then waits for commands still running. Cancelling ctx
+// kills them.
+func (h *HookRunner) Run(ctx context.Context, events <-chan Event) {
+	defer h.wg.Wait()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if event.Type != EventStatusChanged {
+				continue
+			}
+			svc, ok := h.manager.Get(event.ServiceID)
+			if !ok {
+				continue
+			}
+			command := h.global
+			if svc.Config.OnChange != "" {
+				command = svc.Config.OnChange
+			}
+			if command == "" {
+				continue
+			}
+
+			h.wg.Add(1)
+			go func() {
+				defer h.wg.Done()
+				result := h.Exec(ctx, command, event, svc)
+				if h.OnResult != nil {
+					h.OnResult(result)
+				}
+			}()
+		}
+	}
+}
+
+// Exec runs command through the shell with the transition described in its
+// environment.
+func (h *HookRunner) Exec(ctx context.Context, command string, event Event, svc ServiceState) HookResult {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	var output cappedBuffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), hookEnv(event, svc)...)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	// Don't wait forever on output from children the shell left behind
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	err := cmd.Run()
+	return HookResult{
+		Event:    event,
+		Command:  command,
+		Output:   output.String(),
+		Err:      err,
+		Duration: time.Since(start),
+		TimedOut: errors.Is(ctx.Err(), context.DeadlineExceeded),
+		Timeout:  h.timeout,
+	}
+}
+
+// Status describes how the command finished.
+func (r HookResult) Status() string {
+	switch {
+	case r.TimedOut:
+		return fmt.Sprintf("timed out after %s", r.Timeout)
+	case r.Err != nil:
+		return r.Err.Error()
+	default:
+		return fmt.Sprintf("ok (%s)", r.Duration.Round(time.Millisecond))
+	}
+}
+
+func hookEnv(event Event, svc ServiceState) []string {
+	incidentURL := svc.Config.URL
+	for _, inc := range svc.Incidents {
+		if inc.ResolvedAt == nil && inc.URL != "" {
+			incidentURL = inc.URL
+			break
+		}
+	}
+	return []string{
+		"LAZYSTATUS_SERVICE=" + event.ServiceName,
+		"LAZYSTATUS_SERVICE_ID=" + event.ServiceID,
+		"LAZYSTATUS_URL=" + event.URL,
+		"LAZYSTATUS_OLD=" + event.OldLevel.Key(),
+		"LAZYSTATUS_NEW=" + event.NewLevel.Key(),
+		"LAZYSTATUS_INCIDENT_URL=" + incidentURL,
+	}
+}
+
+// cappedBuffer keeps the first maxHookOutput bytes written to it and
+// discards the rest.
+type cappedBuffer struct {
+	mu        sync.Mutex
+	buf       []byte
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	room := maxHookOutput - len(b.buf)
+	if len(p) > room {
+		b.buf = append(b.buf, p[:room]...)
+		b.truncated = true
+	} else {
+		b.buf = append(b.buf, p...)
+	}
+	return len(p), nil
+}
+
+func (b *cappedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.truncated {
+		return string(b.buf) + "\n[output truncated]"
+	}
+	return string(b.buf)
+}
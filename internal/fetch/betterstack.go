@@ -0,0 +1,171 @@
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const betterstackIndexPath = "/index.json"
+
+// betterstackResponse is the JSON:API document behind a Better Stack (formerly
+// Better Uptime) status page. Sections, resources and status reports all
+// arrive mixed together in Included.
+type betterstackResponse struct {
+	Data *struct {
+		Type       string `json:"type"`
+		Attributes struct {
+			CompanyName    string `json:"company_name"`
+			AggregateState string `json:"aggregate_state"`
+		} `json:"attributes"`
+	} `json:"data"`
+	Included []struct {
+		ID         json.Number `json:"id"`
+		Type       string      `json:"type"`
+		Attributes struct {
+			// Sections
+			Name string `json:"name"`
+			// Resources
+			PublicName string      `json:"public_name"`
+			SectionID  json.Number `json:"status_page_section_id"`
+			Status     string      `json:"status"`
+			// Status reports
+			Title          string     `json:"title"`
+			ReportType     string     `json:"report_type"`
+			StartsAt       time.Time  `json:"starts_at"`
+			EndsAt         *time.Time `json:"ends_at"`
+			AggregateState string     `json:"aggregate_state"`
+		} `json:"attributes"`
+	} `json:"included"`
+}
+
+// betterstackProvider reads the index.json served by Better Stack pages.
+type betterstackProvider struct{}
+
+func (betterstackProvider) Name() string { return "betterstack" }
+
+func (betterstackProvider) Detect(u *url.URL) Detection {
+	host := u.Hostname()
+	if u.Path == betterstackIndexPath || strings.HasSuffix(host, ".betteruptime.com") || strings.HasSuffix(host, ".betterstack.com") {
+		return DetectYes
+	}
+	return DetectNo
+}
+
+func (p betterstackProvider) Fetch(ctx context.Context, c *Client, u *url.URL) (*Result, error) {
+	apiURL := url.URL{Scheme: u.Scheme, Host: u.Host, Path: betterstackIndexPath}
+	urlStr := apiURL.String()
+
+	result, err := c.getResult(ctx, p.Name(), urlStr, "application/json", expect(contentJSON, parseBetterstack))
+	if err != nil {
+		return nil, err
+	}
+	result.SourceURL = urlStr
+	return result, nil
+}
+
+func parseBetterstack(body []byte) (*Result, error) {
+	var resp betterstackResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Data == nil || resp.Data.Type != "status_page" {
+		return nil, &ContentTypeError{Want: "a Better Stack status page", Got: "other JSON"}
+	}
+
+	result := &Result{
+		CheckedAt: time.Now(),
+		ParseNote: "Parsed Better Stack status page",
+	}
+
+	sections := make(map[string]string)
+	for _, inc := range resp.Included {
+		if inc.Type == "status_page_section" {
+			sections[inc.ID.String()] = inc.Attributes.Name
+		}
+	}
+
+	now := time.Now()
+	for _, inc := range resp.Included {
+		attrs := inc.Attributes
+		switch inc.Type {
+		case "status_page_resource":
+			result.Components = append(result.Components, Component{
+				ID:     inc.ID.String(),
+				Name:   attrs.PublicName,
+				Status: attrs.Status,
+				Group:  sections[attrs.SectionID.String()],
+				Level:  betterstackLevel(attrs.Status),
+			})
+		case "status_report":
+			// Reports stay in the document after they end
+			if attrs.EndsAt != nil && attrs.EndsAt.Before(now) {
+				continue
+			}
+			if attrs.ReportType == "maintenance" {
+				maint := Maintenance{
+					ID:      inc.ID.String(),
+					Title:   attrs.Title,
+					Status:  "scheduled",
+					Impact:  impactMaintenance,
+					StartAt: attrs.StartsAt,
+				}
+				if attrs.EndsAt != nil {
+					maint.EndAt = *attrs.EndsAt
+				}
+				if !attrs.StartsAt.After(now) {
+					maint.Status = "in_progress"
+				}
+				result.Maintenances = append(result.Maintenances, maint)
+				continue
+			}
+			result.Incidents = append(result.Incidents, Incident{
+				ID:        inc.ID.String(),
+				Title:     attrs.Title,
+				Status:    "ongoing",
+				Impact:    levelImpact(betterstackLevel(attrs.AggregateState)),
+				StartedAt: attrs.StartsAt,
+				UpdatedAt: attrs.StartsAt,
+			})
+		}
+	}
+
+	switch state := resp.Data.Attributes.AggregateState; state {
+	case "operational":
+		result.Level = StatusOperational
+		result.Label = "All Systems Operational"
+	case "degraded":
+		result.Level = StatusDegraded
+		result.Label = "Degraded Performance"
+	case "downtime":
+		result.Level = StatusMajorDisruption
+		result.Label = "Downtime"
+	case "maintenance":
+		result.Level = StatusPlannedMaintenance
+		result.Label = "Under Maintenance"
+	default:
+		result.Level = StatusUnknown
+		result.Label = state
+	}
+
+	return result, nil
+}
+
+// betterstackLevel maps the states Better Stack gives resources, status
+// reports and the page as a whole.
+func betterstackLevel(status string) StatusLevel {
+	switch status {
+	case "operational":
+		return StatusOperational
+	case "maintenance":
+		return StatusPlannedMaintenance
+	case "degraded":
+		return StatusDegraded
+	case "downtime":
+		return StatusMajorDisruption
+	default:
+		return StatusUnknown
+	}
+}
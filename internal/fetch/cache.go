@@ -0,0 +1,128 @@
+package fetch
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// cacheEntry holds the validators for a URL along with the body they
+// describe, and the Results parsed from it by providers that parsed that
+// body alone. Results are kept by provider name, since each provider makes
+// something different of the same body.
+type cacheEntry struct {
+	etag         string
+	lastModified string
+	body         []byte
+	kind         contentKind
+	results      map[string]*Result
+}
+
+// responseCache remembers the last 200 response for each URL that came with
+// an ETag or Last-Modified header, so the next request can be conditional.
+// Entries are replaced, never modified, so they can be read without the lock.
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{entries: make(map[string]*cacheEntry)}
+}
+
+func (rc *responseCache) lookup(urlStr string) *cacheEntry {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.entries[urlStr]
+}
+
+// store records a fresh response, or forgets the URL if the response can't
+// be revalidated.
+func (rc *responseCache) store(urlStr string, resp *response) *cacheEntry {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	entry := &cacheEntry{
+		etag:         resp.header.Get("ETag"),
+		lastModified: resp.header.Get("Last-Modified"),
+		body:         resp.body,
+		kind:         resp.kind,
+	}
+	if entry.etag == "" && entry.lastModified == "" {
+		delete(rc.entries, urlStr)
+		return nil
+	}
+	rc.entries[urlStr] = entry
+	return entry
+}
+
+// storeResult attaches the Result provider parsed from entry's body, unless
+// a newer response has replaced entry in the meantime.
+func (rc *responseCache) storeResult(urlStr string, entry *cacheEntry, provider string, result *Result) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if entry == nil || rc.entries[urlStr] != entry {
+		return
+	}
+	withResult := *entry
+	withResult.results = maps.Clone(entry.results)
+	if withResult.results == nil {
+		withResult.results = make(map[string]*Result)
+	}
+	withResult.results[provider] = result.clone()
+	rc.entries[urlStr] = &withResult
+}
+
+func (e *cacheEntry) resultOf(provider string) *Result {
+	if e == nil {
+		return nil
+	}
+	return e.results[provider]
+}
+
+func (e *cacheEntry) setValidators(req *http.Request) {
+	if e.etag != "" {
+		req.Header.Set("If-None-Match", e.etag)
+	}
+	if e.lastModified != "" {
+		req.Header.Set("If-Modified-Since", e.lastModified)
+	}
+}
+
+// getResult fetches urlStr and parses the body with parse on behalf of the
+// named provider. If the server answers 304 Not Modified, the Result that
+// provider parsed last time is reused instead. The returned Result is the
+// caller's to modify.
+func (c *Client) getResult(ctx context.Context, provider, urlStr, accept string, parse parseFunc) (*Result, error) {
+	resp, err := c.fetchBody(ctx, urlStr, accept)
+	if err != nil {
+		return nil, err
+	}
+	if cached := resp.entry.resultOf(provider); resp.notModified && cached != nil {
+		result := cached.clone()
+		result.CheckedAt = time.Now()
+		result.ParseNote += " (not modified)"
+		return result, nil
+	}
+
+	result, err := parse(resp.body, resp.kind)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.storeResult(urlStr, resp.entry, provider, result)
+	return result, nil
+}
+
+// clone returns a copy of r whose slices can be replaced or re-sliced
+// without affecting r.
+func (r *Result) clone() *Result {
+	cp := *r
+	cp.Incidents = slices.Clone(r.Incidents)
+	cp.Maintenances = slices.Clone(r.Maintenances)
+	cp.Components = slices.Clone(r.Components)
+	return &cp
+}
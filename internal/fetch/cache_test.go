@@ -0,0 +1,65 @@
+package fetch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetResultNotModified(t *testing.T) {
+	var conditional []bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conditional = append(conditional, r.Header.Get("If-None-Match") != "")
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write(loadFixture(t, "rss_incident.xml"))
+	}))
+	defer srv.Close()
+
+	c := NewClient()
+	// Each parser labels its Result with its provider's name
+	get := func(provider string) *Result {
+		t.Helper()
+		result, err := c.getResult(context.Background(), provider, srv.URL, "", func([]byte, contentKind) (*Result, error) {
+			return &Result{Label: provider, ParseNote: "Parsed by " + provider}, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	checks := []struct {
+		provider    string
+		notModified bool
+	}{
+		{"rss", false},
+		{"rss", true},
+		// A 304 to another provider is parsed again from the cached body
+		{"html", false},
+		{"html", true},
+		{"rss", true},
+	}
+	for i, check := range checks {
+		result := get(check.provider)
+		if result.Label != check.provider {
+			t.Errorf("check %d: got the %s result, want %s", i+1, result.Label, check.provider)
+		}
+		if got := strings.HasSuffix(result.ParseNote, "(not modified)"); got != check.notModified {
+			t.Errorf("check %d: got note %q", i+1, result.ParseNote)
+		}
+	}
+
+	for i, got := range conditional {
+		if got != (i > 0) {
+			t.Errorf("got conditional requests %v, want all but the first", conditional)
+			break
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const cachetAPIPath = "/api/v1/"
+
+// Cachet sends numbers as strings on some versions, hence json.Number.
+type cachetComponent struct {
+	ID         json.Number `json:"id"`
+	Name       string      `json:"name"`
+	Status     json.Number `json:"status"`
+	StatusName string      `json:"status_name"`
+	GroupID    json.Number `json:"group_id"`
+	Enabled    *bool       `json:"enabled"`
+}
+
+type cachetGroup struct {
+	ID   json.Number `json:"id"`
+	Name string      `json:"name"`
+}
+
+type cachetIncident struct {
+	ID          json.Number `json:"id"`
+	ComponentID json.Number `json:"component_id"`
+	Name        string      `json:"name"`
+	Status      json.Number `json:"status"`
+	HumanStatus string      `json:"human_status"`
+	Message     string      `json:"message"`
+	Permalink   string      `json:"permalink"`
+	OccurredAt  string      `json:"occurred_at"`
+	ScheduledAt string      `json:"scheduled_at"`
+	CreatedAt   string      `json:"created_at"`
+	UpdatedAt   string      `json:"updated_at"`
+}
+
+// Cachet incident statuses.
+const (
+	cachetScheduled     = "0"
+	cachetInvestigating = "1"
+	cachetIdentified    = "2"
+	cachetWatching      = "3"
+	cachetFixed         = "4"
+)
+
+// cachetOpenStatuses are the incident statuses worth fetching. The API only
+// filters on one status at a time, and listing the newest incidents instead
+// would miss older ones that are still open.
+var cachetOpenStatuses = []string{cachetScheduled, cachetInvestigating, cachetIdentified, cachetWatching}
+
+// cachetProvider reads a self-hosted Cachet page through its v1 API: one
+// request each for components, component groups and recent incidents.
+type cachetProvider struct{}
+
+func (cachetProvider) Name() string { return "cachet" }
+
+func (cachetProvider) Detect(u *url.URL) Detection {
+	if strings.Contains(u.Path, cachetAPIPath) && !strings.Contains(u.Path, gatusStatusesPath) {
+		return DetectYes
+	}
+	return DetectNo
+}
+
+func (cachetProvider) Fetch(ctx context.Context, c *Client, u *url.URL) (*Result, error) {
+	base := url.URL{Scheme: u.Scheme, Host: u.Host}
+	if i := strings.Index(u.Path, cachetAPIPath); i > 0 {
+		// Cachet installed under a subpath
+		base.Path = u.Path[:i]
+	}
+	endpoint := func(path string) string {
+		return base.String() + cachetAPIPath + path
+	}
+
+	var components struct {
+		Data []cachetComponent `json:"data"`
+	}
+	if err := c.getJSON(ctx, endpoint("components?per_page=500"), &components); err != nil {
+		return nil, err
+	}
+	if components.Data == nil {
+		return nil, errNotCachet
+	}
+
+	// Groups only name the tree in the details pane; carry on without them
+	var groups struct {
+		Data []cachetGroup `json:"data"`
+	}
+	groupsErr := c.getJSON(ctx, endpoint("components/groups?per_page=500"), &groups)
+
+	var incidents []cachetIncident
+	for _, status := range cachetOpenStatuses {
+		var page struct {
+			Data []cachetIncident `json:"data"`
+		}
+		if err := c.getJSON(ctx, endpoint("incidents?per_page=100&status="+status), &page); err != nil {
+			return nil, err
+		}
+		incidents = append(incidents, page.Data...)
+	}
+
+	result := parseCachet(components.Data, groups.Data, incidents, time.Now())
+	result.SourceURL = endpoint("components")
+	if groupsErr != nil {
+		result.ParseNote += fmt.Sprintf("; components ungrouped (%v)", groupsErr)
+	}
+	return result, nil
+}
+
+var errNotCachet = &ContentTypeError{Want: "a Cachet component list", Got: "other JSON"}
+
+func parseCachet(components []cachetComponent, groups []cachetGroup, incidents []cachetIncident, now time.Time) *Result {
+	result := &Result{
+		CheckedAt: now,
+		Level:     StatusOperational,
+		Label:     "All Systems Operational",
+		ParseNote: "Parsed Cachet API",
+	}
+
+	groupNames := make(map[string]string)
+	for _, g := range groups {
+		groupNames[g.ID.String()] = g.Name
+	}
+	componentNames := make(map[string]string)
+	componentStatuses := make(map[string]string)
+	for _, comp := range components {
+		componentNames[comp.ID.String()] = comp.Name
+		componentStatuses[comp.ID.String()] = comp.Status.String()
+		if comp.Enabled != nil && !*comp.Enabled {
+			continue
+		}
+		level := cachetComponentLevel(comp.Status.String())
+		status := firstNonEmpty(comp.StatusName, cachetStatusNames[comp.Status.String()])
+		result.Components = append(result.Components, Component{
+			ID:     comp.ID.String(),
+			Name:   comp.Name,
+			Status: status,
+			Group:  groupNames[comp.GroupID.String()],
+			Level:  level,
+		})
+		if level > result.Level {
+			result.Level = level
+			result.Label = status
+		}
+	}
+
+	// Older versions of Cachet ignore the status filter and send every
+	// incident for each status
+	seen := make(map[string]bool)
+	for _, inc := range incidents {
+		if inc.Status.String() == cachetFixed || seen[inc.ID.String()] {
+			continue
+		}
+		seen[inc.ID.String()] = true
+
+		var affected []string
+		if name, ok := componentNames[inc.ComponentID.String()]; ok {
+			affected = []string{name}
+		}
+		started := parseTimestamp(firstNonEmpty(inc.OccurredAt, inc.ScheduledAt, inc.CreatedAt))
+		updated := parseTimestamp(inc.UpdatedAt)
+
+		if inc.Status.String() == cachetScheduled {
+			// Scheduled maintenance is an incident with status 0
+			if started.Before(now.Add(-24 * time.Hour)) {
+				continue
+			}
+			result.Maintenances = append(result.Maintenances, Maintenance{
+				ID:         inc.ID.String(),
+				Title:      inc.Name,
+				Status:     "scheduled",
+				Impact:     impactMaintenance,
+				StartAt:    started,
+				Components: affected,
+			})
+			continue
+		}
+
+		incident := Incident{
+			ID:         inc.ID.String(),
+			Title:      inc.Name,
+			URL:        inc.Permalink,
+			Status:     strings.ToLower(inc.HumanStatus),
+			Impact:     levelImpact(cachetComponentLevel(componentStatuses[inc.ComponentID.String()])),
+			StartedAt:  started,
+			UpdatedAt:  updated,
+			Components: affected,
+		}
+		if inc.Message != "" {
+			incident.Updates = []IncidentUpdate{{Body: inc.Message, Status: incident.Status, CreatedAt: updated}}
+		}
+		result.Incidents = append(result.Incidents, incident)
+	}
+
+	return result
+}
+
+// cachetStatusNames names component statuses for versions of Cachet that
+// don't send status_name.
+var cachetStatusNames = map[string]string{
+	"1": "Operational",
+	"2": "Performance Issues",
+	"3": "Partial Outage",
+	"4": "Major Outage",
+}
+
+func cachetComponentLevel(status string) StatusLevel {
+	switch status {
+	case "1":
+		return StatusOperational
+	case "2", "3":
+		return StatusDegraded
+	case "4":
+		return StatusMajorDisruption
+	default:
+		return StatusUnknown
+	}
+}
@@ -0,0 +1,109 @@
+package fetch
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxBodySize is used when Client.MaxBodySize is zero.
+const DefaultMaxBodySize = 5 << 20
+
+// contentKind is what a response body turned out to be.
+type contentKind int
+
+const (
+	contentOther contentKind = iota
+	contentJSON
+	contentXML
+	contentHTML
+	contentText
+)
+
+func (k contentKind) String() string {
+	switch k {
+	case contentJSON:
+		return "JSON"
+	case contentXML:
+		return "XML"
+	case contentHTML:
+		return "HTML"
+	case contentText:
+		return "plain text"
+	default:
+		return "binary data"
+	}
+}
+
+// sniffContent works out what body is from its first bytes, and only falls
+// back to the Content-Type header when they're inconclusive. Servers often
+// label JSON as text/plain, or answer an API path with an HTML error page.
+func sniffContent(contentType string, body []byte) contentKind {
+	start := bytes.TrimLeft(bytes.TrimPrefix(body, []byte("\xef\xbb\xbf")), " \t\r\n")
+	hasPrefix := func(prefix string) bool {
+		return len(start) >= len(prefix) && strings.EqualFold(string(start[:len(prefix)]), prefix)
+	}
+
+	switch {
+	case len(start) == 0:
+	case start[0] == '{' || start[0] == '[':
+		return contentJSON
+	case hasPrefix("<!doctype html") || hasPrefix("<html"):
+		return contentHTML
+	case hasPrefix("<?xml") || hasPrefix("<rss") || hasPrefix("<feed") || hasPrefix("<rdf:"):
+		return contentXML
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return contentJSON
+	case mediaType == "text/xml" || mediaType == "application/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return contentXML
+	case mediaType == "text/html":
+		return contentHTML
+	case len(start) > 0 && start[0] == '<':
+		return contentHTML
+	case strings.HasPrefix(http.DetectContentType(body), "text/"):
+		return contentText
+	default:
+		return contentOther
+	}
+}
+
+// ContentTypeError is returned when a provider gets a response it can't
+// parse, e.g. an HTML login page where it expected a JSON API.
+type ContentTypeError struct {
+	Want string
+	Got  string
+}
+
+func (e *ContentTypeError) Error() string {
+	return fmt.Sprintf("expected %s, got %s", e.Want, e.Got)
+}
+
+// TooLargeError is returned instead of reading a body past the client's
+// limit.
+type TooLargeError struct {
+	Limit int64
+}
+
+func (e *TooLargeError) Error() string {
+	return fmt.Sprintf("response is larger than the %d KB limit", e.Limit/1024)
+}
+
+// parseFunc turns a response body of the given kind into a Result.
+type parseFunc func(body []byte, kind contentKind) (*Result, error)
+
+// expect returns a parseFunc that only hands body to parse if it is of kind
+// want.
+func expect(want contentKind, parse func([]byte) (*Result, error)) parseFunc {
+	return func(body []byte, kind contentKind) (*Result, error) {
+		if kind != want {
+			return nil, &ContentTypeError{Want: want.String(), Got: kind.String()}
+		}
+		return parse(body)
+	}
+}
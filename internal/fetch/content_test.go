@@ -0,0 +1,128 @@
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSniffContent(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        contentKind
+	}{
+		{"JSON", "application/json", `{"page": {}}`, contentJSON},
+		{"JSON as text", "text/plain; charset=utf-8", `{"page": {}}`, contentJSON},
+		{"JSON array as HTML", "text/html", "\xef\xbb\xbf\n  [1, 2]", contentJSON},
+		{"HTML error page as JSON", "application/json", "<!DOCTYPE html><html><body>502</body></html>", contentHTML},
+		{"HTML as XML", "application/xml", "<HTML><body></body></HTML>", contentHTML},
+		{"feed as HTML", "text/html", `<?xml version="1.0"?><rss></rss>`, contentXML},
+		{"feed without declaration", "text/plain", "<feed></feed>", contentXML},
+		{"bare markup", "", "<div>All good</div>", contentHTML},
+		{"empty JSON", "application/vnd.api+json", "", contentJSON},
+		{"plain text", "", "All systems operational", contentText},
+		{"binary", "application/octet-stream", "\x89PNG\r\n\x1a\n\x00\x00", contentOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sniffContent(tt.contentType, []byte(tt.body)); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchUnusableResponse(t *testing.T) {
+	large := bytes.Repeat([]byte("<p>All Systems Operational</p>\n"), 200)
+
+	tests := []struct {
+		name        string
+		provider    string
+		contentType string
+		body        []byte
+		streamed    bool // sent without a Content-Length
+		level       StatusLevel
+		note        string
+	}{
+		{
+			name:        "JSON labelled as text",
+			provider:    "statuspage",
+			contentType: "text/plain",
+			body:        loadFixture(t, "statuspage_operational.json"),
+			level:       StatusOperational,
+			note:        "Parsed Statuspage.io JSON API",
+		},
+		{
+			name:        "HTML error page labelled as JSON",
+			provider:    "statuspage",
+			contentType: "application/json",
+			body:        []byte("<!DOCTYPE html><html><body><h1>Sign in</h1></body></html>"),
+			level:       StatusParseError,
+			note:        "Unusable response: statuspage: expected JSON, got HTML",
+		},
+		{
+			name:        "feed labelled as HTML",
+			provider:    "rss",
+			contentType: "text/html",
+			body:        loadFixture(t, "rss_incident.xml"),
+			level:       StatusDegraded,
+		},
+		{
+			name:        "binary where a feed was expected",
+			provider:    "rss",
+			contentType: "application/octet-stream",
+			body:        []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03"),
+			level:       StatusParseError,
+			note:        "Unusable response: rss: expected XML, got binary data",
+		},
+		{
+			name:        "too large",
+			provider:    "html",
+			contentType: "text/html",
+			body:        large,
+			level:       StatusParseError,
+			note:        "Unusable response: html: response is larger than the 2 KB limit",
+		},
+		{
+			name:        "too large, streamed",
+			provider:    "html",
+			contentType: "text/html",
+			body:        large,
+			streamed:    true,
+			level:       StatusParseError,
+			note:        "Unusable response: html: response is larger than the 2 KB limit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				if tt.streamed {
+					// Flushing first leaves the length unknown
+					w.(http.Flusher).Flush()
+				}
+				w.Write(tt.body)
+			}))
+			defer srv.Close()
+
+			c := NewClientWithPolicy(RetryPolicy{MaxAttempts: 1}, 0, 0)
+			c.MaxBodySize = 2048
+			result, err := c.Fetch(context.Background(), Target{URL: srv.URL, Provider: tt.provider})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Level != tt.level {
+				t.Errorf("got level %v, want %v (%s)", result.Level, tt.level, result.ParseNote)
+			}
+			if tt.note != "" && result.ParseNote != tt.note {
+				t.Errorf("got note %q, want %q", result.ParseNote, tt.note)
+			}
+		})
+	}
+}
@@ -3,15 +3,12 @@ package fetch
 import (
 	"context"
 	"encoding/json"
-	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
-	"strings"
 	"time"
-
-	"golang.org/x/net/html"
 )
 
 type StatusLevel int
@@ -33,458 +30,317 @@ type IncidentUpdate struct {
 }
 
 type Incident struct {
-	ID         string          `json:"id"`
-	Title      string          `json:"name"`
-	Status     string          `json:"status"`
-	Impact     string          `json:"impact"`
-	StartedAt  time.Time       `json:"started_at"`
-	UpdatedAt  time.Time       `json:"updated_at"`
-	ResolvedAt *time.Time      `json:"resolved_at,omitempty"`
+	ID         string           `json:"id"`
+	Title      string           `json:"name"`
+	URL        string           `json:"shortlink"`
+	Status     string           `json:"status"`
+	Impact     string           `json:"impact"`
+	StartedAt  time.Time        `json:"started_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+	ResolvedAt *time.Time       `json:"resolved_at,omitempty"`
 	Updates    []IncidentUpdate `json:"incident_updates,omitempty"`
+	Components []string         `json:"-"` // names of affected components
+	// Affected products and regions by name and ID, on pages that cover many
+	Products    []string `json:"-"`
+	ProductIDs  []string `json:"-"`
+	Locations   []string `json:"-"`
+	LocationIDs []string `json:"-"`
 }
 
 type Maintenance struct {
-	ID      string          `json:"id"`
-	Title   string          `json:"name"`
-	Status  string          `json:"status"`
-	Impact  string          `json:"impact"`
-	StartAt time.Time       `json:"scheduled_for"`
-	EndAt   time.Time       `json:"scheduled_until"`
-	Updates []IncidentUpdate `json:"incident_updates,omitempty"`
-}
-
-type Result struct {
-	Level         StatusLevel
-	Label         string
-	CheckedAt     time.Time
-	Incidents     []Incident
-	Maintenances  []Maintenance
-	SourceURL     string
-	ParseNote     string
-}
-
-type statuspageResponse struct {
-	Page struct {
-		Name      string    `json:"name"`
-		UpdatedAt time.Time `json:"updated_at"`
-	} `json:"page"`
-	Status struct {
-		Indicator   string `json:"indicator"`
-		Description string `json:"description"`
-	} `json:"status"`
-	Incidents            []Incident    `json:"incidents"`
-	ScheduledMaintenances []Maintenance `json:"scheduled_maintenances"`
+	ID         string           `json:"id"`
+	Title      string           `json:"name"`
+	Status     string           `json:"status"`
+	Impact     string           `json:"impact"`
+	StartAt    time.Time        `json:"scheduled_for"`
+	EndAt      time.Time        `json:"scheduled_until"`
+	Updates    []IncidentUpdate `json:"incident_updates,omitempty"`
+	Components []string         `json:"-"` // names of affected components
 }
 
-type rssItem struct {
-	Title       string `xml:"title"`
-	Description string `xml:"description"`
-	Link        string `xml:"link"`
-	PubDate     string `xml:"pubDate"`
+// Component is a single monitored part of a status page, such as "Actions" on
+// GitHub. Group is the name of the parent component group, if any.
+type Component struct {
+	ID     string
+	Name   string
+	Status string
+	Group  string
+	Level  StatusLevel
 }
 
-type rssFeed struct {
-	Channel struct {
-		Title string    `xml:"title"`
-		Items []rssItem `xml:"item"`
-	} `xml:"channel"`
+type Result struct {
+	Level        StatusLevel
+	Label        string
+	CheckedAt    time.Time
+	Incidents    []Incident
+	Maintenances []Maintenance
+	Components   []Component
+	SourceURL    string
+	ParseNote    string
+	Provider     string
+	// Fallback is set when only a DetectFallback provider, such as html,
+	// could read the page.
+	Fallback bool
 }
 
-type atomEntry struct {
-	Title   string `xml:"title"`
-	Summary string `xml:"summary"`
-	Updated string `xml:"updated"`
-	Link    struct {
-		Href string `xml:"href,attr"`
-	} `xml:"link"`
+const userAgent = "lazystatus/0.1 (+https://github.com/jakeasaurus/lazystatus)"
+
+// Target describes a status page to fetch. Provider pins a registered
+// provider by name; when empty the registry is consulted in detection order.
+// Components narrows the result to the components the caller cares about,
+// and Scope to the products and regions.
+type Target struct {
+	URL        string
+	Provider   string
+	Components ComponentFilter
+	Scope      Scope
 }
 
-type atomFeed struct {
-	Title   string       `xml:"title"`
-	Entries []atomEntry `xml:"entry"`
-}
+// Circuit breaker defaults for NewClient.
+const (
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = time.Minute
+)
 
 type Client struct {
-	http *http.Client
+	http    *http.Client
+	retry   RetryPolicy
+	breaker *breaker
+	cache   *responseCache
+
+	// MaxBodySize caps how much of a response is read, in bytes. Zero means
+	// DefaultMaxBodySize.
+	MaxBodySize int64
 }
 
 func NewClient() *Client {
+	return NewClientWithPolicy(DefaultRetryPolicy, DefaultBreakerThreshold, DefaultBreakerCooldown)
+}
+
+// NewClientWithPolicy returns a client that retries as described by retry and
+// stops requesting a host for cooldown after breakerThreshold consecutive
+// checks failed to reach it. A check counts once per host, however many
+// requests detection and retries made. A threshold of 0 disables the breaker, except for
+// honoring rate limits.
+func NewClientWithPolicy(retry RetryPolicy, breakerThreshold int, cooldown time.Duration) *Client {
+	if retry.MaxAttempts < 1 {
+		retry.MaxAttempts = 1
+	}
 	return &Client{
 		http: &http.Client{
 			Timeout:   30 * time.Second,
 			Transport: &http.Transport{Proxy: http.ProxyFromEnvironment},
 		},
+		retry:   retry,
+		breaker: newBreaker(breakerThreshold, cooldown),
+		cache:   newResponseCache(),
 	}
 }
 
-func (c *Client) Fetch(ctx context.Context, rawURL string) (*Result, error) {
+func (c *Client) Fetch(ctx context.Context, target Target) (*Result, error) {
 	result := &Result{
 		CheckedAt: time.Now(),
-		SourceURL: rawURL,
+		SourceURL: target.URL,
 		Level:     StatusUnknown,
 	}
 
-	parsedURL, err := url.Parse(rawURL)
+	parsedURL, err := url.Parse(target.URL)
 	if err != nil {
 		result.Level = StatusParseError
 		result.ParseNote = fmt.Sprintf("Invalid URL: %v", err)
 		return result, nil
 	}
 
-	// Check if URL is an RSS/Atom feed
-	if strings.HasSuffix(parsedURL.Path, ".rss") || strings.HasSuffix(parsedURL.Path, ".xml") || 
-	   strings.Contains(parsedURL.Path, "/rss") || strings.Contains(parsedURL.Path, "/feed") ||
-	   strings.Contains(parsedURL.Path, "/atom") {
-		rssResult, err := c.fetchRSS(ctx, rawURL)
-		if err == nil {
-			return rssResult, nil
+	var providers []Provider
+	if target.Provider != "" {
+		p := Lookup(target.Provider)
+		if p == nil {
+			result.Level = StatusParseError
+			result.ParseNote = fmt.Sprintf("Unknown provider %q", target.Provider)
+			return result, nil
 		}
-		result.ParseNote = fmt.Sprintf("RSS fetch failed: %v; trying JSON", err)
-	}
-
-	var tryJSON bool
-	if strings.Contains(parsedURL.Path, "/api/v2/summary.json") {
-		tryJSON = true
+		providers = []Provider{p}
 	} else {
-		apiURL := *parsedURL
-		apiURL.Path = "/api/v2/summary.json"
-		tryJSON = true
-		rawURL = apiURL.String()
+		providers = candidates(parsedURL)
 	}
 
-	if tryJSON {
-		jsonResult, err := c.fetchJSON(ctx, rawURL)
+	ctx, tally := withTally(ctx)
+	defer func() { tally.flush(c.breaker, time.Now()) }()
+
+	var lastErr error
+	for _, p := range providers {
+		providerResult, err := p.Fetch(ctx, c, parsedURL)
 		if err == nil {
-			return jsonResult, nil
+			providerResult.Provider = p.Name()
+			providerResult.Fallback = p.Detect(parsedURL) == DetectFallback
+			applyComponentFilter(providerResult, target.Components)
+			applyScope(providerResult, target.Scope)
+			return providerResult, nil
 		}
-		result.ParseNote = fmt.Sprintf("JSON fetch failed: %v; falling back to HTML", err)
+		lastErr = fmt.Errorf("%s: %w", p.Name(), err)
 	}
 
-	htmlResult, err := c.fetchHTML(ctx, parsedURL.String())
-	if err != nil {
-		result.Level = StatusConnectionError
-		result.ParseNote = fmt.Sprintf("Connection error: %v", err)
+	// The page was reached, but nothing could make sense of it
+	var typeErr *ContentTypeError
+	var sizeErr *TooLargeError
+	if errors.As(lastErr, &typeErr) || errors.As(lastErr, &sizeErr) {
+		result.Level = StatusParseError
+		result.ParseNote = fmt.Sprintf("Unusable response: %v", lastErr)
 		return result, nil
 	}
 
-	return htmlResult, nil
+	result.Level = StatusConnectionError
+	result.ParseNote = fmt.Sprintf("Connection error: %v", lastErr)
+	return result, nil
 }
 
-func (c *Client) fetchJSON(ctx context.Context, urlStr string) (*Result, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", urlStr, nil)
+// getJSON fetches urlStr for a provider that needs several requests, and
+// decodes the JSON response into v.
+func (c *Client) getJSON(ctx context.Context, urlStr string, v any) error {
+	resp, err := c.fetchBody(ctx, urlStr, "application/json")
 	if err != nil {
-		return nil, err
+		return err
 	}
+	if resp.kind != contentJSON {
+		return &ContentTypeError{Want: contentJSON.String(), Got: resp.kind.String()}
+	}
+	return json.Unmarshal(resp.body, v)
+}
 
-	req.Header.Set("User-Agent", "lazystatus/0.1 (+https://github.com/jakeasaurus/lazystatus)")
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := c.http.Do(req)
+// fetchBody performs a GET request on behalf of a provider and returns a 200
+// response, sniffing what its body is. Transient failures are retried with
+// backoff, and hosts that keep failing or rate-limit us are left alone for a
+// while. Requests for a URL seen before with validators are conditional, and
+// a 304 returns the cached body with notModified set.
+func (c *Client) fetchBody(ctx context.Context, urlStr, accept string) (*response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", urlStr, nil)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	req.Header.Set("User-Agent", userAgent)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
 	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	cached := c.cache.lookup(urlStr)
+	if cached != nil {
+		cached.setValidators(req)
 	}
+	host := req.URL.Host
 
-	var spResp statuspageResponse
-	if err := json.Unmarshal(body, &spResp); err != nil {
+	if err := c.breaker.allow(host, time.Now()); err != nil {
 		return nil, err
 	}
 
-	result := &Result{
-		CheckedAt:    time.Now(),
-		SourceURL:    urlStr,
-		Incidents:    spResp.Incidents,
-		Maintenances: spResp.ScheduledMaintenances,
-		ParseNote:    "Parsed Statuspage.io JSON API",
-	}
-
-	for i := range result.Incidents {
-		if len(result.Incidents[i].Updates) > 0 {
-			result.Incidents[i].StartedAt = result.Incidents[i].Updates[len(result.Incidents[i].Updates)-1].CreatedAt
-			result.Incidents[i].UpdatedAt = result.Incidents[i].Updates[0].CreatedAt
-		}
-		if result.Incidents[i].Status == "resolved" || result.Incidents[i].Status == "completed" {
-			t := result.Incidents[i].UpdatedAt
-			result.Incidents[i].ResolvedAt = &t
+	var resp *response
+	for attempt := 1; ; attempt++ {
+		resp, err = c.do(req)
+		if err == nil || attempt >= c.retry.MaxAttempts || !retryable(err) || ctx.Err() != nil {
+			break
 		}
-	}
-
-	switch strings.ToLower(spResp.Status.Indicator) {
-	case "none":
-		result.Level = StatusOperational
-		result.Label = "All Systems Operational"
-	case "minor":
-		result.Level = StatusDegraded
-		result.Label = spResp.Status.Description
-	case "major":
-		result.Level = StatusMajorDisruption
-		result.Label = spResp.Status.Description
-	case "critical":
-		result.Level = StatusMajorDisruption
-		result.Label = spResp.Status.Description
-	case "maintenance":
-		result.Level = StatusPlannedMaintenance
-		result.Label = spResp.Status.Description
-	default:
-		result.Level = StatusUnknown
-		result.Label = spResp.Status.Description
-	}
-
-	if len(result.Maintenances) > 0 && result.Level == StatusOperational {
-		result.Level = StatusPlannedMaintenance
-		result.Label = "Scheduled Maintenance"
-	}
 
-	return result, nil
-}
-
-func (c *Client) fetchHTML(ctx context.Context, urlStr string) (*Result, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", urlStr, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("User-Agent", "lazystatus/0.1 (+https://github.com/jakeasaurus/lazystatus)")
+		delay := c.retry.backoff(attempt)
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+			if statusErr.RetryAfter > c.retry.MaxDelay {
+				break
+			}
+			delay = statusErr.RetryAfter
+		}
 
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return nil, err
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	// Our own cancellation says nothing about the host
+	if ctx.Err() == nil || err == nil {
+		c.recordRequest(ctx, host, err)
 	}
-
-	doc, err := html.Parse(resp.Body)
 	if err != nil {
 		return nil, err
 	}
 
-	result := &Result{
-		CheckedAt: time.Now(),
-		SourceURL: urlStr,
-		ParseNote: "Parsed HTML fallback",
-		Level:     StatusUnknown,
+	if resp.notModified && cached != nil {
+		resp.body, resp.kind, resp.entry = cached.body, cached.kind, cached
+		return resp, nil
 	}
-
-	text := extractText(doc)
-	textLower := strings.ToLower(text)
-
-	if strings.Contains(textLower, "all systems operational") ||
-		(strings.Contains(textLower, "operational") && !strings.Contains(textLower, "not operational")) {
-		result.Level = StatusOperational
-		result.Label = "All Systems Operational"
-	} else if strings.Contains(textLower, "major outage") || strings.Contains(textLower, "major disruption") {
-		result.Level = StatusMajorDisruption
-		result.Label = "Major Disruption Detected"
-	} else if strings.Contains(textLower, "partial outage") || strings.Contains(textLower, "degraded") {
-		result.Level = StatusDegraded
-		result.Label = "Degraded Performance Detected"
-	} else if strings.Contains(textLower, "maintenance") || strings.Contains(textLower, "scheduled") {
-		result.Level = StatusPlannedMaintenance
-		result.Label = "Maintenance Detected"
-	} else {
-		result.Level = StatusParseError
-		result.Label = "Unable to determine status"
-		result.ParseNote = "Could not find status keywords in HTML"
-	}
-
-	return result, nil
+	resp.kind = sniffContent(resp.header.Get("Content-Type"), resp.body)
+	resp.entry = c.cache.store(urlStr, resp)
+	return resp, nil
 }
 
-func (c *Client) fetchRSS(ctx context.Context, urlStr string) (*Result, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", urlStr, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("User-Agent", "lazystatus/0.1 (+https://github.com/jakeasaurus/lazystatus)")
-	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml, text/xml")
+type response struct {
+	header      http.Header
+	body        []byte
+	kind        contentKind
+	notModified bool
+	// entry is the cached copy of body, if it can be revalidated.
+	entry *cacheEntry
+}
 
+func (c *Client) do(req *http.Request) (*response, error) {
 	resp, err := c.http.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	// Only a conditional request can be answered with 304
+	if resp.StatusCode == http.StatusNotModified && req.Header.Get("If-None-Match")+req.Header.Get("If-Modified-Since") != "" {
+		return &response{header: resp.Header, notModified: true}, nil
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+		return nil, &StatusError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	limit := c.MaxBodySize
+	if limit <= 0 {
+		limit = DefaultMaxBodySize
 	}
-
-	result := &Result{
-		CheckedAt: time.Now(),
-		SourceURL: urlStr,
-		ParseNote: "Parsed RSS/Atom feed",
+	if resp.ContentLength > limit {
+		return nil, &TooLargeError{Limit: limit}
 	}
-
-	// Try RSS first
-	var rss rssFeed
-	if err := xml.Unmarshal(body, &rss); err == nil && len(rss.Channel.Items) > 0 {
-		return parseRSSItems(rss.Channel.Items, result), nil
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
 	}
-
-	// Try Atom
-	var atom atomFeed
-	if err := xml.Unmarshal(body, &atom); err == nil && len(atom.Entries) > 0 {
-		return parseAtomEntries(atom.Entries, result), nil
+	if int64(len(body)) > limit {
+		return nil, &TooLargeError{Limit: limit}
 	}
-
-	return nil, fmt.Errorf("failed to parse as RSS or Atom feed")
+	return &response{header: resp.Header, body: body}, nil
 }
 
-func parseRSSItems(items []rssItem, result *Result) *Result {
-	if len(items) == 0 {
-		result.Level = StatusUnknown
-		result.Label = "No feed items found"
-		return result
-	}
-
-	// Status is determined by the MOST RECENT item only
-	latestItem := items[0]
-	latestLower := strings.ToLower(latestItem.Title + " " + latestItem.Description)
-	var currentStatus StatusLevel = StatusOperational
-	var currentLabel string = "All Systems Operational"
-	
-	// Check if latest item indicates everything is OK
-	if strings.Contains(latestLower, "resolved") || strings.Contains(latestLower, "operating normally") {
-		currentStatus = StatusOperational
-		currentLabel = "All Systems Operational"
-	} else if strings.Contains(latestLower, "major") || strings.Contains(latestLower, "outage") || strings.Contains(latestLower, "disruption") {
-		currentStatus = StatusMajorDisruption
-		currentLabel = latestItem.Title
-	} else if strings.Contains(latestLower, "degraded") || strings.Contains(latestLower, "degradation") ||
-		strings.Contains(latestLower, "impact") || strings.Contains(latestLower, "latenc") ||
-		strings.Contains(latestLower, "error") {
-		currentStatus = StatusDegraded
-		currentLabel = latestItem.Title
-	} else if strings.Contains(latestLower, "maintenance") || strings.Contains(latestLower, "scheduled") {
-		currentStatus = StatusPlannedMaintenance
-		currentLabel = latestItem.Title
-	}
-
-	// Collect all incidents from last 7 days for history
-	sevenDaysAgo := time.Now().AddDate(0, 0, -7)
-	var recentIncidents []Incident
-
-	for _, item := range items {
-		// Parse pubDate (RSS format: "Mon, 20 Oct 2025 15:53:00 PDT")
-		pubDate, err := time.Parse("Mon, 02 Jan 2006 15:04:05 MST", item.PubDate)
-		if err != nil {
-			// If parse fails, include it anyway (assume recent)
-			pubDate = time.Now()
-		}
-
-		// Skip items older than 7 days
-		if pubDate.Before(sevenDaysAgo) {
-			continue
-		}
-
-		titleLower := strings.ToLower(item.Title)
-		descLower := strings.ToLower(item.Description)
-		combined := titleLower + " " + descLower
-
-		// Determine if this is an incident or maintenance
-		if strings.Contains(combined, "maintenance") || strings.Contains(combined, "scheduled") {
-			// Skip maintenance items for incident list
-			continue
-		}
-
-		// Check if it's resolved - do this FIRST
-		var resolvedAt *time.Time
-		itemStatus := "investigating"
-		isResolved := strings.Contains(combined, "resolved") || strings.Contains(combined, "operating normally")
-		if isResolved {
-			resolvedAt = &pubDate
-			itemStatus = "resolved"
-		}
-
-		// Determine impact level for incident history
-		impact := "minor"
-		if strings.Contains(combined, "major") || strings.Contains(combined, "outage") || strings.Contains(combined, "disruption") {
-			impact = "major"
-		} else if strings.Contains(combined, "degraded") || strings.Contains(combined, "degradation") ||
-			strings.Contains(combined, "impact") || strings.Contains(combined, "latenc") ||
-			strings.Contains(combined, "error") {
-			impact = "minor"
-		} else if !isResolved {
-			// Unrecognized non-resolved item, skip it
-			continue
-		}
-
-		// Add to incidents list
-		recentIncidents = append(recentIncidents, Incident{
-			ID:         item.Link,
-			Title:      item.Title,
-			Status:     itemStatus,
-			Impact:     impact,
-			StartedAt:  pubDate,
-			UpdatedAt:  pubDate,
-			ResolvedAt: resolvedAt,
-		})
-	}
-
-	result.Level = currentStatus
-	result.Label = currentLabel
-	result.Incidents = recentIncidents
-
-	// Add maintenance info if latest item is about maintenance
-	if currentStatus == StatusPlannedMaintenance {
-		pubDate, _ := time.Parse("Mon, 02 Jan 2006 15:04:05 MST", latestItem.PubDate)
-		if pubDate.IsZero() {
-			pubDate = time.Now()
-		}
-		result.Maintenances = []Maintenance{{
-			ID:      latestItem.Link,
-			Title:   latestItem.Title,
-			Status:  "scheduled",
-			StartAt: pubDate,
-			EndAt:   pubDate.Add(24 * time.Hour),
-		}}
-	}
-
-	return result
+// timestampLayouts are the formats self-hosted status pages use for times,
+// which are often a plain database timestamp rather than RFC 3339.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.000",
+	"2006-01-02 15:04:05",
 }
 
-func parseAtomEntries(entries []atomEntry, result *Result) *Result {
-	if len(entries) == 0 {
-		result.Level = StatusUnknown
-		result.Label = "No feed entries found"
-		return result
-	}
-
-	// Convert first entry to RSS format and reuse logic
-	item := rssItem{
-		Title:       entries[0].Title,
-		Description: entries[0].Summary,
-		Link:        entries[0].Link.Href,
-		PubDate:     entries[0].Updated,
+// parseTimestamp parses s in any of timestampLayouts, treating times without
+// a zone as UTC. It returns the zero time if s can't be parsed.
+func parseTimestamp(s string) time.Time {
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
 	}
-	return parseRSSItems([]rssItem{item}, result)
+	return time.Time{}
 }
 
-func extractText(n *html.Node) string {
-	if n.Type == html.TextNode {
-		return n.Data
-	}
-
-	var text string
-	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		text += extractText(c) + " "
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
 	}
-	return text
+	return ""
 }
@@ -0,0 +1,291 @@
+package fetch
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ComponentFilter selects the components a service cares about. Patterns are
+// matched case-insensitively against a component's name or its group, and
+// may use shell globs such as "*us-east-1*". An empty Include matches every
+// component; Exclude always wins.
+type ComponentFilter struct {
+	Include []string
+	Exclude []string
+}
+
+func (f ComponentFilter) IsEmpty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0
+}
+
+func (f ComponentFilter) Matches(c Component) bool {
+	for _, pattern := range f.Exclude {
+		if matchComponent(pattern, c) {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, pattern := range f.Include {
+		if matchComponent(pattern, c) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchComponent(pattern string, c Component) bool {
+	return matchPattern(pattern, c.Name, c.Group)
+}
+
+// matchPattern reports whether pattern, a name or a glob, matches any of
+// candidates, ignoring case.
+func matchPattern(pattern string, candidates ...string) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+		candidate = strings.ToLower(candidate)
+		if !strings.ContainsAny(pattern, "*?[") {
+			if pattern == candidate {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(pattern, candidate); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// applyComponentFilter narrows result down to the components selected by f
+// and recomputes its level from them and from the incidents and maintenances
+// that affect them, rather than from the page-wide indicator. Incidents and
+// maintenances that name no components are kept as page-wide, since many
+// providers never link them to components.
+func applyComponentFilter(result *Result, f ComponentFilter) {
+	if f.IsEmpty() {
+		return
+	}
+	if len(result.Components) == 0 {
+		result.ParseNote += "; component filter ignored (page lists no components)"
+		return
+	}
+
+	matched := make(map[string]bool)
+	var components []Component
+	for _, c := range result.Components {
+		if f.Matches(c) {
+			components = append(components, c)
+			matched[c.Name] = true
+		}
+	}
+	result.Components = components
+
+	affects := func(names []string) bool {
+		if len(names) == 0 {
+			return true
+		}
+		for _, name := range names {
+			if matched[name] {
+				return true
+			}
+		}
+		return false
+	}
+
+	var incidents []Incident
+	for _, inc := range result.Incidents {
+		if affects(inc.Components) {
+			incidents = append(incidents, inc)
+		}
+	}
+	result.Incidents = incidents
+
+	var maintenances []Maintenance
+	for _, maint := range result.Maintenances {
+		if affects(maint.Components) {
+			maintenances = append(maintenances, maint)
+		}
+	}
+	result.Maintenances = maintenances
+
+	if len(components) == 0 {
+		result.Level = StatusUnknown
+		result.Label = "No components match filter"
+		return
+	}
+
+	level := StatusOperational
+	var affected []string
+	for _, c := range components {
+		if severity(c.Level) > severity(StatusOperational) {
+			affected = append(affected, c.Name)
+		}
+		if severity(c.Level) > severity(level) {
+			level = c.Level
+		}
+	}
+	for _, inc := range incidents {
+		if inc.ResolvedAt != nil {
+			continue
+		}
+		if l := impactLevel(inc.Impact); severity(l) > severity(level) {
+			level = l
+		}
+	}
+	if len(maintenances) > 0 && level == StatusOperational {
+		level = StatusPlannedMaintenance
+	}
+
+	result.Level = level
+	switch {
+	case level == StatusOperational:
+		result.Label = "All Watched Components Operational"
+	case len(affected) > 0:
+		result.Label = fmt.Sprintf("Affected: %s", strings.Join(affected, ", "))
+	case level == StatusPlannedMaintenance:
+		result.Label = "Scheduled Maintenance"
+	default:
+		result.Label = "Incident Affecting Watched Components"
+	}
+}
+
+// Scope selects the products and regions a service cares about on a page
+// that covers many of them, such as a cloud provider's. Patterns match like
+// ComponentFilter patterns; an empty list matches everything.
+type Scope struct {
+	Products []string
+	Regions  []string
+}
+
+func (s Scope) IsEmpty() bool {
+	return len(s.Products) == 0 && len(s.Regions) == 0
+}
+
+// Matches reports whether an incident affects the scope, by product and
+// region name or ID. An incident that doesn't list its locations is assumed
+// to affect every region.
+func (s Scope) Matches(inc Incident) bool {
+	products := matchAny(s.Products, inc.Products) || matchAny(s.Products, inc.ProductIDs)
+	regions := len(inc.Locations) == 0 && len(inc.LocationIDs) == 0 ||
+		matchAny(s.Regions, inc.Locations) || matchAny(s.Regions, inc.LocationIDs)
+	return products && regions
+}
+
+func matchAny(patterns, candidates []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if matchPattern(pattern, candidates...) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyScope drops incidents outside s and recomputes the level from the
+// open incidents that are left.
+func applyScope(result *Result, s Scope) {
+	if s.IsEmpty() || len(result.Incidents) == 0 {
+		return
+	}
+
+	var incidents []Incident
+	listed := false
+	for _, inc := range result.Incidents {
+		if len(inc.Products)+len(inc.ProductIDs)+len(inc.Locations)+len(inc.LocationIDs) > 0 {
+			listed = true
+		}
+		if s.Matches(inc) {
+			incidents = append(incidents, inc)
+		}
+	}
+	if !listed {
+		result.ParseNote += "; products/regions ignored (incidents don't list them)"
+		return
+	}
+	result.Incidents = incidents
+
+	level := StatusOperational
+	var open []Incident
+	for _, inc := range incidents {
+		if inc.ResolvedAt != nil {
+			continue
+		}
+		open = append(open, inc)
+		if l := impactLevel(inc.Impact); severity(l) > severity(level) {
+			level = l
+		}
+	}
+
+	result.Level = level
+	switch {
+	case len(open) == 0:
+		result.Label = "No Incidents in Watched Products or Regions"
+	case len(open) == 1:
+		result.Label = open[0].Title
+	default:
+		result.Label = fmt.Sprintf("%d Incidents in Watched Products or Regions", len(open))
+	}
+}
+
+// severity orders levels from healthy to broken. Unknown ranks below
+// operational so it never masks a real status.
+func severity(level StatusLevel) int {
+	switch level {
+	case StatusOperational:
+		return 1
+	case StatusPlannedMaintenance:
+		return 2
+	case StatusDegraded:
+		return 3
+	case StatusMajorDisruption:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// Incident impacts, as Statuspage.io names them. Other providers give their
+// incidents and maintenances these too, so impactLevel can read any page's.
+const (
+	impactNone        = "none"
+	impactMinor       = "minor"
+	impactMajor       = "major"
+	impactCritical    = "critical"
+	impactMaintenance = "maintenance"
+)
+
+func impactLevel(impact string) StatusLevel {
+	switch strings.ToLower(impact) {
+	case impactMinor:
+		return StatusDegraded
+	case impactMajor, impactCritical:
+		return StatusMajorDisruption
+	case impactMaintenance:
+		return StatusPlannedMaintenance
+	default:
+		return StatusOperational
+	}
+}
+
+// levelImpact is the impact that impactLevel reads as level, for pages that
+// only say how badly an incident affects them as a status.
+func levelImpact(level StatusLevel) string {
+	switch level {
+	case StatusDegraded:
+		return impactMinor
+	case StatusMajorDisruption:
+		return impactMajor
+	case StatusPlannedMaintenance:
+		return impactMaintenance
+	default:
+		return impactNone
+	}
+}
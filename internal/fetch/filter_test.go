@@ -0,0 +1,208 @@
+package fetch
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApplyComponentFilter(t *testing.T) {
+	resolved := time.Now()
+	newResult := func() *Result {
+		return &Result{
+			Level: StatusMajorDisruption,
+			Label: "Partial System Outage",
+			Components: []Component{
+				{Name: "API", Level: StatusOperational},
+				{Name: "Webhooks", Level: StatusMajorDisruption},
+				{Name: "Search", Group: "EU", Level: StatusOperational},
+			},
+			Incidents: []Incident{
+				{ID: "linked", Impact: "major", Components: []string{"Webhooks"}},
+				{ID: "page-wide", Impact: "minor"},
+				{ID: "resolved", Impact: "critical", ResolvedAt: &resolved},
+			},
+			Maintenances: []Maintenance{
+				{ID: "search", Components: []string{"Search"}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		filter    ComponentFilter
+		level     StatusLevel
+		label     string
+		incidents []string
+	}{
+		{
+			name:      "include healthy",
+			filter:    ComponentFilter{Include: []string{"api"}},
+			level:     StatusDegraded,
+			label:     "Incident Affecting Watched Components",
+			incidents: []string{"page-wide", "resolved"},
+		},
+		{
+			name:      "include broken",
+			filter:    ComponentFilter{Include: []string{"Web*"}},
+			level:     StatusMajorDisruption,
+			label:     "Affected: Webhooks",
+			incidents: []string{"linked", "page-wide", "resolved"},
+		},
+		{
+			name:      "group",
+			filter:    ComponentFilter{Include: []string{"eu"}},
+			level:     StatusDegraded,
+			label:     "Incident Affecting Watched Components",
+			incidents: []string{"page-wide", "resolved"},
+		},
+		{
+			name:      "no match",
+			filter:    ComponentFilter{Include: []string{"Billing"}},
+			level:     StatusUnknown,
+			label:     "No components match filter",
+			incidents: []string{"page-wide", "resolved"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newResult()
+			applyComponentFilter(result, tt.filter)
+			if result.Level != tt.level || result.Label != tt.label {
+				t.Errorf("got %v %q, want %v %q", result.Level, result.Label, tt.level, tt.label)
+			}
+			var ids []string
+			for _, inc := range result.Incidents {
+				ids = append(ids, inc.ID)
+			}
+			if len(ids) != len(tt.incidents) {
+				t.Fatalf("got incidents %v, want %v", ids, tt.incidents)
+			}
+			for i := range ids {
+				if ids[i] != tt.incidents[i] {
+					t.Errorf("got incidents %v, want %v", ids, tt.incidents)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyScope(t *testing.T) {
+	resolved := time.Now()
+	newResult := func() *Result {
+		return &Result{
+			Level: StatusMajorDisruption,
+			Label: "2 Open Incidents",
+			Incidents: []Incident{
+				{
+					ID:          "gce",
+					Title:       "Instances unreachable",
+					Impact:      "major",
+					Products:    []string{"Google Compute Engine"},
+					ProductIDs:  []string{"L3MkI8fmKDiVNcTBJ3RA"},
+					Locations:   []string{"Iowa (us-central1)"},
+					LocationIDs: []string{"us-central1"},
+				},
+				{
+					ID:         "bigquery",
+					Title:      "Elevated query latency",
+					Impact:     "minor",
+					Products:   []string{"Google BigQuery"},
+					ProductIDs: []string{"9CcrhHUcFevXPSVaSxkf"},
+				},
+				{
+					ID:          "gcs",
+					Title:       "Storage errors",
+					Impact:      "major",
+					ResolvedAt:  &resolved,
+					Products:    []string{"Cloud Storage"},
+					ProductIDs:  []string{"UwaYoXQ5bHYHG6EdiPB8"},
+					Locations:   []string{"Belgium (europe-west1)"},
+					LocationIDs: []string{"europe-west1"},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		scope     Scope
+		level     StatusLevel
+		label     string
+		incidents []string
+	}{
+		{
+			name:      "product name and region ID",
+			scope:     Scope{Products: []string{"google compute engine"}, Regions: []string{"us-central1"}},
+			level:     StatusMajorDisruption,
+			label:     "Instances unreachable",
+			incidents: []string{"gce"},
+		},
+		{
+			name:      "product ID",
+			scope:     Scope{Products: []string{"L3MkI8fmKDiVNcTBJ3RA"}},
+			level:     StatusMajorDisruption,
+			label:     "Instances unreachable",
+			incidents: []string{"gce"},
+		},
+		{
+			name:      "region name, and no locations",
+			scope:     Scope{Regions: []string{"Iowa*"}},
+			level:     StatusMajorDisruption,
+			label:     "2 Incidents in Watched Products or Regions",
+			incidents: []string{"gce", "bigquery"},
+		},
+		{
+			name:      "resolved",
+			scope:     Scope{Regions: []string{"europe-*"}},
+			level:     StatusDegraded,
+			label:     "Elevated query latency",
+			incidents: []string{"bigquery", "gcs"},
+		},
+		{
+			name:  "no match",
+			scope: Scope{Products: []string{"Cloud Run"}},
+			level: StatusOperational,
+			label: "No Incidents in Watched Products or Regions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newResult()
+			applyScope(result, tt.scope)
+			if result.Level != tt.level || result.Label != tt.label {
+				t.Errorf("got %v %q, want %v %q", result.Level, result.Label, tt.level, tt.label)
+			}
+			var ids []string
+			for _, inc := range result.Incidents {
+				ids = append(ids, inc.ID)
+			}
+			if len(ids) != len(tt.incidents) {
+				t.Fatalf("got incidents %v, want %v", ids, tt.incidents)
+			}
+			for i := range ids {
+				if ids[i] != tt.incidents[i] {
+					t.Errorf("got incidents %v, want %v", ids, tt.incidents)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyScopeUnlisted(t *testing.T) {
+	result := &Result{
+		Level:     StatusDegraded,
+		Label:     "Elevated API Errors",
+		ParseNote: "Parsed Statuspage.io API",
+		Incidents: []Incident{{ID: "api", Impact: "minor"}},
+	}
+	applyScope(result, Scope{Regions: []string{"us-central1"}})
+	if result.Level != StatusDegraded || len(result.Incidents) != 1 {
+		t.Errorf("got %v with %d incidents, want it unchanged", result.Level, len(result.Incidents))
+	}
+	if !strings.Contains(result.ParseNote, "products/regions ignored") {
+		t.Errorf("got note %q", result.ParseNote)
+	}
+}
@@ -0,0 +1,98 @@
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const gatusStatusesPath = "/api/v1/endpoints/statuses"
+
+type gatusEndpoint struct {
+	Name    string `json:"name"`
+	Group   string `json:"group"`
+	Key     string `json:"key"`
+	Results []struct {
+		Success bool `json:"success"`
+	} `json:"results"`
+}
+
+// gatusProvider reads the endpoint statuses behind a Gatus dashboard.
+type gatusProvider struct{}
+
+func (gatusProvider) Name() string { return "gatus" }
+
+func (gatusProvider) Detect(u *url.URL) Detection {
+	if strings.Contains(u.Path, gatusStatusesPath) {
+		return DetectYes
+	}
+	return DetectNo
+}
+
+func (p gatusProvider) Fetch(ctx context.Context, c *Client, u *url.URL) (*Result, error) {
+	apiURL := url.URL{Scheme: u.Scheme, Host: u.Host, Path: gatusStatusesPath}
+	if i := strings.Index(u.Path, gatusStatusesPath); i > 0 {
+		// Gatus served under a subpath
+		apiURL.Path = u.Path[:i] + gatusStatusesPath
+	}
+	urlStr := apiURL.String()
+
+	result, err := c.getResult(ctx, p.Name(), urlStr, "application/json", expect(contentJSON, parseGatus))
+	if err != nil {
+		return nil, err
+	}
+	result.SourceURL = urlStr
+	return result, nil
+}
+
+func parseGatus(body []byte) (*Result, error) {
+	var endpoints []gatusEndpoint
+	if err := json.Unmarshal(body, &endpoints); err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		CheckedAt: time.Now(),
+		Level:     StatusOperational,
+		Label:     "All Endpoints Healthy",
+		ParseNote: "Parsed Gatus endpoint statuses",
+	}
+
+	unhealthy := 0
+	for _, ep := range endpoints {
+		if ep.Key == "" {
+			return nil, &ContentTypeError{Want: "Gatus endpoint statuses", Got: "other JSON"}
+		}
+		comp := Component{
+			ID:     ep.Key,
+			Name:   ep.Name,
+			Status: "unknown",
+			Group:  ep.Group,
+			Level:  StatusUnknown,
+		}
+		// Results are oldest first
+		if len(ep.Results) > 0 {
+			latest := ep.Results[len(ep.Results)-1]
+			comp.Status, comp.Level = "healthy", StatusOperational
+			if !latest.Success {
+				comp.Status, comp.Level = "unhealthy", StatusMajorDisruption
+				unhealthy++
+			}
+		}
+		result.Components = append(result.Components, comp)
+	}
+
+	switch {
+	case unhealthy == 1:
+		result.Level = StatusMajorDisruption
+		result.Label = "1 Endpoint Unhealthy"
+	case unhealthy > 1:
+		result.Level = StatusMajorDisruption
+		result.Label = fmt.Sprintf("%d Endpoints Unhealthy", unhealthy)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,154 @@
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const gcpIncidentsPath = "/incidents.json"
+
+type gcpLocation struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type gcpIncident struct {
+	ID               string     `json:"id"`
+	Begin            time.Time  `json:"begin"`
+	End              *time.Time `json:"end"`
+	Modified         time.Time  `json:"modified"`
+	ExternalDesc     string     `json:"external_desc"`
+	StatusImpact     string     `json:"status_impact"`
+	URI              string     `json:"uri"`
+	AffectedProducts []struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	} `json:"affected_products"`
+	CurrentlyAffectedLocations []gcpLocation `json:"currently_affected_locations"`
+	MostRecentUpdate           struct {
+		When              time.Time     `json:"when"`
+		Text              string        `json:"text"`
+		Status            string        `json:"status"`
+		AffectedLocations []gcpLocation `json:"affected_locations"`
+	} `json:"most_recent_update"`
+}
+
+// gcpProvider reads the incident history behind the Google Cloud status
+// dashboard. The page covers every product in every region, so only open
+// incidents are kept, tagged with their products and locations for a
+// service's Scope to narrow down.
+type gcpProvider struct{}
+
+func (gcpProvider) Name() string { return "gcp" }
+
+func (gcpProvider) Detect(u *url.URL) Detection {
+	if u.Hostname() == "status.cloud.google.com" || strings.HasSuffix(u.Path, gcpIncidentsPath) {
+		return DetectYes
+	}
+	return DetectNo
+}
+
+func (p gcpProvider) Fetch(ctx context.Context, c *Client, u *url.URL) (*Result, error) {
+	apiURL := url.URL{Scheme: u.Scheme, Host: u.Host, Path: gcpIncidentsPath}
+	if strings.HasSuffix(u.Path, gcpIncidentsPath) {
+		apiURL.Path = u.Path
+	}
+	urlStr := apiURL.String()
+	pageURL := url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/"}
+
+	result, err := c.getResult(ctx, p.Name(), urlStr, "application/json", expect(contentJSON, func(body []byte) (*Result, error) {
+		return parseGCP(body, pageURL.String())
+	}))
+	if err != nil {
+		return nil, err
+	}
+	result.SourceURL = urlStr
+	return result, nil
+}
+
+// parseGCP keeps the open incidents from incidents.json. Incident links are
+// relative to pageURL.
+func parseGCP(body []byte, pageURL string) (*Result, error) {
+	var incidents []gcpIncident
+	if err := json.Unmarshal(body, &incidents); err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		CheckedAt: time.Now(),
+		Level:     StatusOperational,
+		Label:     "No Open Incidents",
+		ParseNote: "Parsed Google Cloud incidents",
+	}
+
+	for _, inc := range incidents {
+		if inc.ID == "" {
+			return nil, &ContentTypeError{Want: "Google Cloud incidents", Got: "other JSON"}
+		}
+		if inc.End != nil && !inc.End.IsZero() {
+			continue
+		}
+
+		update := inc.MostRecentUpdate
+		incident := Incident{
+			ID:        inc.ID,
+			Title:     inc.ExternalDesc,
+			URL:       pageURL + strings.TrimPrefix(inc.URI, "/"),
+			Status:    strings.ToLower(update.Status),
+			Impact:    levelImpact(gcpLevel(inc.StatusImpact)),
+			StartedAt: inc.Begin,
+			UpdatedAt: inc.Modified,
+		}
+		if update.Text != "" {
+			incident.Updates = []IncidentUpdate{{
+				Body:      update.Text,
+				Status:    incident.Status,
+				CreatedAt: update.When,
+			}}
+		}
+		for _, p := range inc.AffectedProducts {
+			incident.Products = append(incident.Products, p.Title)
+			incident.ProductIDs = append(incident.ProductIDs, p.ID)
+		}
+		locations := inc.CurrentlyAffectedLocations
+		if len(locations) == 0 {
+			locations = update.AffectedLocations
+		}
+		for _, loc := range locations {
+			incident.Locations = append(incident.Locations, loc.Title)
+			incident.LocationIDs = append(incident.LocationIDs, loc.ID)
+		}
+		result.Incidents = append(result.Incidents, incident)
+
+		if l := impactLevel(incident.Impact); severity(l) > severity(result.Level) {
+			result.Level = l
+		}
+	}
+
+	switch len(result.Incidents) {
+	case 0:
+	case 1:
+		result.Label = result.Incidents[0].Title
+	default:
+		result.Label = fmt.Sprintf("%d Open Incidents", len(result.Incidents))
+	}
+
+	return result, nil
+}
+
+// gcpLevel maps a status_impact: outages are major, disruptions degrade the
+// service, and informational notices don't affect it.
+func gcpLevel(statusImpact string) StatusLevel {
+	switch statusImpact {
+	case "SERVICE_OUTAGE":
+		return StatusMajorDisruption
+	case "SERVICE_DISRUPTION":
+		return StatusDegraded
+	default:
+		return StatusOperational
+	}
+}
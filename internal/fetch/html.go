@@ -0,0 +1,103 @@
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// htmlProvider scrapes status keywords out of an arbitrary HTML page. It is
+// the last resort when no structured provider works, so it goes by what the
+// server actually sent: feeds and Statuspage.io JSON at unexpected URLs are
+// parsed as such rather than as HTML.
+type htmlProvider struct{}
+
+func (htmlProvider) Name() string { return "html" }
+
+func (htmlProvider) Detect(u *url.URL) Detection { return DetectFallback }
+
+func (p htmlProvider) Fetch(ctx context.Context, c *Client, u *url.URL) (*Result, error) {
+	urlStr := u.String()
+
+	result, err := c.getResult(ctx, p.Name(), urlStr, "", parseAny)
+	if err != nil {
+		return nil, err
+	}
+	result.SourceURL = urlStr
+	return result, nil
+}
+
+// parseAny picks a parser for body by its content.
+func parseAny(body []byte, kind contentKind) (*Result, error) {
+	switch kind {
+	case contentJSON:
+		result, err := parseStatuspage(body)
+		if err != nil {
+			return nil, errNotStatuspage
+		}
+		return result, nil
+	case contentXML:
+		if result, err := parseFeed(body); err == nil {
+			return result, nil
+		}
+		// Not a feed, so most likely XHTML
+		return parseHTML(body)
+	case contentHTML, contentText:
+		return parseHTML(body)
+	default:
+		return nil, &ContentTypeError{Want: "HTML", Got: kind.String()}
+	}
+}
+
+func parseHTML(body []byte) (*Result, error) {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		CheckedAt: time.Now(),
+		ParseNote: "Parsed HTML fallback",
+		Level:     StatusUnknown,
+	}
+
+	text := extractText(doc)
+	textLower := strings.ToLower(text)
+
+	if strings.Contains(textLower, "all systems operational") ||
+		(strings.Contains(textLower, "operational") && !strings.Contains(textLower, "not operational")) {
+		result.Level = StatusOperational
+		result.Label = "All Systems Operational"
+	} else if strings.Contains(textLower, "major outage") || strings.Contains(textLower, "major disruption") {
+		result.Level = StatusMajorDisruption
+		result.Label = "Major Disruption Detected"
+	} else if strings.Contains(textLower, "partial outage") || strings.Contains(textLower, "degraded") {
+		result.Level = StatusDegraded
+		result.Label = "Degraded Performance Detected"
+	} else if strings.Contains(textLower, "maintenance") || strings.Contains(textLower, "scheduled") {
+		result.Level = StatusPlannedMaintenance
+		result.Label = "Maintenance Detected"
+	} else {
+		result.Level = StatusParseError
+		result.Label = "Unable to determine status"
+		result.ParseNote = "Could not find status keywords in HTML"
+	}
+
+	return result, nil
+}
+
+func extractText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+
+	var text string
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		text += extractText(c) + " "
+	}
+	return text
+}
@@ -0,0 +1,67 @@
+package fetch
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseHTML(t *testing.T) {
+	tests := []struct {
+		fixture string
+		level   StatusLevel
+		label   string
+	}{
+		{"html_degraded.html", StatusDegraded, "Degraded Performance Detected"},
+		{"html_unknown.html", StatusParseError, "Unable to determine status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			result, err := parseHTML(loadFixture(t, tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Level != tt.level || result.Label != tt.label {
+				t.Errorf("got %v %q, want %v %q", result.Level, result.Label, tt.level, tt.label)
+			}
+		})
+	}
+}
+
+func TestParseAny(t *testing.T) {
+	tests := []struct {
+		fixture string
+		kind    contentKind
+		level   StatusLevel
+		wantErr bool
+	}{
+		{"statuspage_incident.json", contentJSON, StatusMajorDisruption, false},
+		{"rss_resolved.xml", contentXML, StatusOperational, false},
+		{"html_degraded.html", contentHTML, StatusDegraded, false},
+		{"html_degraded.html", contentOther, StatusUnknown, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture+"/"+tt.kind.String(), func(t *testing.T) {
+			result, err := parseAny(loadFixture(t, tt.fixture), tt.kind)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got level %v, want an error", result.Level)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Level != tt.level {
+				t.Errorf("got %v, want %v", result.Level, tt.level)
+			}
+		})
+	}
+
+	_, err := parseAny([]byte(`{"data": []}`), contentJSON)
+	var ctErr *ContentTypeError
+	if !errors.As(err, &ctErr) {
+		t.Errorf("unrecognised JSON: got %v, want a ContentTypeError", err)
+	}
+}
@@ -0,0 +1,263 @@
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// incident.io status pages load their data from /proxy/<host> on the page's
+// own domain.
+const incidentioProxyPath = "/proxy/"
+
+type incidentioResponse struct {
+	Summary *struct {
+		Name      string `json:"name"`
+		PublicURL string `json:"public_url"`
+		Structure struct {
+			Items []struct {
+				Component *incidentioComponent `json:"component"`
+				Group     *struct {
+					Name       string                `json:"name"`
+					Hidden     bool                  `json:"hidden"`
+					Components []incidentioComponent `json:"components"`
+				} `json:"group"`
+			} `json:"items"`
+		} `json:"structure"`
+		OngoingIncidents       []incidentioIncident    `json:"ongoing_incidents"`
+		InProgressMaintenances []incidentioMaintenance `json:"in_progress_maintenances"`
+		ScheduledMaintenances  []incidentioMaintenance `json:"scheduled_maintenances"`
+		AffectedComponents     []incidentioImpact      `json:"affected_components"`
+	} `json:"summary"`
+}
+
+type incidentioComponent struct {
+	ID     string `json:"component_id"`
+	Name   string `json:"name"`
+	Hidden bool   `json:"hidden"`
+}
+
+// incidentioImpact is a component's status while it is affected.
+type incidentioImpact struct {
+	ComponentID string `json:"component_id"`
+	Status      string `json:"status"`
+}
+
+type incidentioIncident struct {
+	ID                 string             `json:"id"`
+	Name               string             `json:"name"`
+	Status             string             `json:"status"`
+	CurrentWorstImpact string             `json:"current_worst_impact"`
+	PublishedAt        time.Time          `json:"published_at"`
+	LastUpdateAt       time.Time          `json:"last_update_at"`
+	LastUpdateMessage  string             `json:"last_update_message"`
+	AffectedComponents []incidentioImpact `json:"affected_components"`
+}
+
+type incidentioMaintenance struct {
+	ID                 string             `json:"id"`
+	Name               string             `json:"name"`
+	Status             string             `json:"status"`
+	StartedAt          time.Time          `json:"started_at"`
+	StartsAt           time.Time          `json:"starts_at"`
+	ScheduledEndAt     time.Time          `json:"scheduled_end_at"`
+	EndsAt             time.Time          `json:"ends_at"`
+	AffectedComponents []incidentioImpact `json:"affected_components"`
+}
+
+// incidentioProvider reads the JSON behind incident.io-hosted status pages.
+// Most of them are on the vendor's own domain, so any other URL is probed.
+type incidentioProvider struct{}
+
+func (incidentioProvider) Name() string { return "incidentio" }
+
+func (incidentioProvider) Detect(u *url.URL) Detection {
+	if strings.HasPrefix(u.Path, incidentioProxyPath) || strings.HasSuffix(u.Hostname(), ".incident.io") {
+		return DetectYes
+	}
+	return DetectProbe
+}
+
+func (p incidentioProvider) Fetch(ctx context.Context, c *Client, u *url.URL) (*Result, error) {
+	apiURL := url.URL{Scheme: u.Scheme, Host: u.Host, Path: incidentioProxyPath + u.Hostname()}
+	if strings.HasPrefix(u.Path, incidentioProxyPath) {
+		apiURL.Path = u.Path
+	}
+	urlStr := apiURL.String()
+	pageURL := url.URL{Scheme: u.Scheme, Host: u.Host}
+
+	result, err := c.getResult(ctx, p.Name(), urlStr, "application/json", expect(contentJSON, func(body []byte) (*Result, error) {
+		return parseIncidentio(body, pageURL.String())
+	}))
+	if err != nil {
+		return nil, err
+	}
+	result.SourceURL = urlStr
+	return result, nil
+}
+
+// parseIncidentio builds a Result from a page summary. pageURL is used to
+// link incidents when the summary doesn't give the page's public URL.
+func parseIncidentio(body []byte, pageURL string) (*Result, error) {
+	var resp incidentioResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	summary := resp.Summary
+	if summary == nil {
+		return nil, &ContentTypeError{Want: "an incident.io status page", Got: "other JSON"}
+	}
+	if summary.PublicURL != "" {
+		pageURL = strings.TrimSuffix(summary.PublicURL, "/")
+	}
+
+	result := &Result{
+		CheckedAt: time.Now(),
+		Level:     StatusOperational,
+		Label:     "All Systems Operational",
+		ParseNote: "Parsed incident.io status page",
+	}
+
+	// Components not listed as affected are operational
+	statuses := make(map[string]string)
+	for _, impact := range summary.AffectedComponents {
+		statuses[impact.ComponentID] = impact.Status
+	}
+	names := make(map[string]string)
+	addComponent := func(c incidentioComponent, group string) {
+		names[c.ID] = c.Name
+		if c.Hidden {
+			return
+		}
+		status := statuses[c.ID]
+		if status == "" {
+			status = "operational"
+		}
+		result.Components = append(result.Components, Component{
+			ID:     c.ID,
+			Name:   c.Name,
+			Status: status,
+			Group:  group,
+			Level:  incidentioComponentLevel(status),
+		})
+	}
+	for _, item := range summary.Structure.Items {
+		switch {
+		case item.Component != nil:
+			addComponent(*item.Component, "")
+		case item.Group != nil && !item.Group.Hidden:
+			for _, c := range item.Group.Components {
+				addComponent(c, item.Group.Name)
+			}
+		}
+	}
+	componentNames := func(impacts []incidentioImpact) []string {
+		var affected []string
+		for _, impact := range impacts {
+			if name, ok := names[impact.ComponentID]; ok {
+				affected = append(affected, name)
+			}
+		}
+		return affected
+	}
+
+	worst := ""
+	for _, inc := range summary.OngoingIncidents {
+		incident := Incident{
+			ID:         inc.ID,
+			Title:      inc.Name,
+			URL:        fmt.Sprintf("%s/incidents/%s", pageURL, inc.ID),
+			Status:     inc.Status,
+			Impact:     levelImpact(incidentioComponentLevel(inc.CurrentWorstImpact)),
+			StartedAt:  inc.PublishedAt,
+			UpdatedAt:  inc.LastUpdateAt,
+			Components: componentNames(inc.AffectedComponents),
+		}
+		if inc.LastUpdateMessage != "" {
+			incident.Updates = []IncidentUpdate{{
+				Body:      inc.LastUpdateMessage,
+				Status:    inc.Status,
+				CreatedAt: inc.LastUpdateAt,
+			}}
+		}
+		result.Incidents = append(result.Incidents, incident)
+		worst = worseIncidentioImpact(worst, inc.CurrentWorstImpact)
+	}
+	for _, impact := range summary.AffectedComponents {
+		worst = worseIncidentioImpact(worst, impact.Status)
+	}
+
+	for _, maint := range summary.InProgressMaintenances {
+		result.Maintenances = append(result.Maintenances, incidentioMaintenanceFrom(maint, maint.StartedAt, maint.ScheduledEndAt, componentNames))
+	}
+	for _, maint := range summary.ScheduledMaintenances {
+		result.Maintenances = append(result.Maintenances, incidentioMaintenanceFrom(maint, maint.StartsAt, maint.EndsAt, componentNames))
+	}
+
+	switch worst {
+	case "full_outage":
+		result.Level = StatusMajorDisruption
+		result.Label = "Full Outage"
+	case "partial_outage":
+		result.Level = StatusDegraded
+		result.Label = "Partial Outage"
+	case "degraded_performance":
+		result.Level = StatusDegraded
+		result.Label = "Degraded Performance"
+	default:
+		// Upcoming maintenance is listed but doesn't affect the level
+		if len(summary.InProgressMaintenances) > 0 {
+			result.Level = StatusPlannedMaintenance
+			result.Label = "Maintenance in Progress"
+		}
+	}
+
+	return result, nil
+}
+
+func incidentioMaintenanceFrom(maint incidentioMaintenance, start, end time.Time, componentNames func([]incidentioImpact) []string) Maintenance {
+	return Maintenance{
+		ID:         maint.ID,
+		Title:      maint.Name,
+		Status:     maint.Status,
+		Impact:     impactMaintenance,
+		StartAt:    start,
+		EndAt:      end,
+		Components: componentNames(maint.AffectedComponents),
+	}
+}
+
+// incidentioImpactRank orders the impacts incident.io reports; anything
+// else, such as under_maintenance, ranks below all of them.
+var incidentioImpactRank = map[string]int{
+	"degraded_performance": 1,
+	"partial_outage":       2,
+	"full_outage":          3,
+}
+
+func worseIncidentioImpact(a, b string) string {
+	if incidentioImpactRank[b] > incidentioImpactRank[a] {
+		return b
+	}
+	return a
+}
+
+// incidentioComponentLevel maps a component status. An incident's
+// current_worst_impact uses the same names.
+func incidentioComponentLevel(status string) StatusLevel {
+	switch status {
+	case "operational":
+		return StatusOperational
+	case "under_maintenance":
+		return StatusPlannedMaintenance
+	case "degraded_performance", "partial_outage":
+		return StatusDegraded
+	case "full_outage":
+		return StatusMajorDisruption
+	default:
+		return StatusUnknown
+	}
+}
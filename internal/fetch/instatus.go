@@ -0,0 +1,143 @@
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const instatusSummaryPath = "/summary.json"
+
+type instatusResponse struct {
+	Page *struct {
+		Name   string `json:"name"`
+		URL    string `json:"url"`
+		Status string `json:"status"`
+	} `json:"page"`
+	ActiveIncidents []struct {
+		ID      string    `json:"id"`
+		Name    string    `json:"name"`
+		Started time.Time `json:"started"`
+		Status  string    `json:"status"`
+		Impact  string    `json:"impact"`
+		URL     string    `json:"url"`
+	} `json:"activeIncidents"`
+	ActiveMaintenances []struct {
+		ID     string    `json:"id"`
+		Name   string    `json:"name"`
+		Start  time.Time `json:"start"`
+		Status string    `json:"status"`
+		// Duration is in minutes, sent as either a number or a string
+		Duration json.Number `json:"duration"`
+		URL      string      `json:"url"`
+	} `json:"activeMaintenances"`
+}
+
+// instatusProvider reads the summary.json served by Instatus pages.
+type instatusProvider struct{}
+
+func (instatusProvider) Name() string { return "instatus" }
+
+func (instatusProvider) Detect(u *url.URL) Detection {
+	if u.Path == instatusSummaryPath || strings.HasSuffix(u.Hostname(), ".instatus.com") {
+		return DetectYes
+	}
+	return DetectNo
+}
+
+func (p instatusProvider) Fetch(ctx context.Context, c *Client, u *url.URL) (*Result, error) {
+	apiURL := url.URL{Scheme: u.Scheme, Host: u.Host, Path: instatusSummaryPath}
+	urlStr := apiURL.String()
+
+	result, err := c.getResult(ctx, p.Name(), urlStr, "application/json", expect(contentJSON, parseInstatus))
+	if err != nil {
+		return nil, err
+	}
+	result.SourceURL = urlStr
+	return result, nil
+}
+
+func parseInstatus(body []byte) (*Result, error) {
+	var resp instatusResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Page == nil || resp.Page.Status == "" {
+		return nil, &ContentTypeError{Want: "an Instatus summary", Got: "other JSON"}
+	}
+
+	result := &Result{
+		CheckedAt: time.Now(),
+		ParseNote: "Parsed Instatus summary",
+	}
+
+	worst := StatusOperational
+	for _, inc := range resp.ActiveIncidents {
+		incident := Incident{
+			ID:        inc.ID,
+			Title:     inc.Name,
+			URL:       inc.URL,
+			Status:    strings.ToLower(inc.Status),
+			Impact:    levelImpact(instatusImpactLevel(inc.Impact)),
+			StartedAt: inc.Started,
+			UpdatedAt: inc.Started,
+		}
+		result.Incidents = append(result.Incidents, incident)
+		if level := instatusImpactLevel(inc.Impact); level > worst {
+			worst = level
+		}
+	}
+
+	for _, maint := range resp.ActiveMaintenances {
+		m := Maintenance{
+			ID:      maint.ID,
+			Title:   maint.Name,
+			Status:  strings.ToLower(maint.Status),
+			Impact:  impactMaintenance,
+			StartAt: maint.Start,
+		}
+		if maint.Status == "INPROGRESS" {
+			m.Status = "in_progress"
+		}
+		if minutes, err := maint.Duration.Int64(); err == nil && minutes > 0 {
+			m.EndAt = maint.Start.Add(time.Duration(minutes) * time.Minute)
+		}
+		result.Maintenances = append(result.Maintenances, m)
+	}
+
+	switch resp.Page.Status {
+	case "UP":
+		result.Level = StatusOperational
+		result.Label = "All Systems Operational"
+	case "HASISSUES":
+		// The page only says something is wrong; the incidents say how bad
+		result.Level = max(worst, StatusDegraded)
+		result.Label = "Some Systems Affected"
+		if result.Level == StatusMajorDisruption {
+			result.Label = "Major Outage"
+		}
+	case "UNDERMAINTENANCE":
+		result.Level = StatusPlannedMaintenance
+		result.Label = "Under Maintenance"
+	default:
+		result.Level = StatusUnknown
+		result.Label = resp.Page.Status
+	}
+
+	return result, nil
+}
+
+func instatusImpactLevel(impact string) StatusLevel {
+	switch impact {
+	case "MAJOROUTAGE":
+		return StatusMajorDisruption
+	case "PARTIALOUTAGE", "DEGRADEDPERFORMANCE":
+		return StatusDegraded
+	case "UNDERMAINTENANCE":
+		return StatusPlannedMaintenance
+	default:
+		return StatusOperational
+	}
+}
@@ -0,0 +1,120 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+)
+
+// Detection is how confident a provider is that it can handle a URL.
+type Detection int
+
+const (
+	// DetectNo means the provider should only be used when pinned.
+	DetectNo Detection = iota
+	// DetectFallback means the provider can parse almost anything, badly.
+	DetectFallback
+	// DetectProbe means the provider is worth trying speculatively. Every
+	// probe is another request to the host on each detection, so only use
+	// it when the URL's shape suggests the provider, or for hosts like
+	// Statuspage.io and incident.io that serve most pages from the vendor's
+	// own domain.
+	DetectProbe
+	// DetectYes means the URL clearly belongs to the provider.
+	DetectYes
+)
+
+// Provider is a self-contained status page backend. Detect must not do any
+// network I/O; Fetch performs the requests and parses them into a Result.
+// Callers may remember Result.SourceURL and pass it back to Fetch later, so
+// a provider must accept the endpoint it reported as well as the page URL.
+type Provider interface {
+	Name() string
+	Detect(u *url.URL) Detection
+	Fetch(ctx context.Context, c *Client, u *url.URL) (*Result, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = []Provider{
+		statuspageProvider{},
+		incidentioProvider{},
+		instatusProvider{},
+		betterstackProvider{},
+		statusioProvider{},
+		cachetProvider{},
+		uptimekumaProvider{},
+		gatusProvider{},
+		upptimeProvider{},
+		gcpProvider{},
+		rssProvider{},
+		htmlProvider{},
+	}
+)
+
+// Register adds a provider to the registry. Providers registered later are
+// tried after existing ones with the same Detection.
+func Register(p Provider) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, existing := range registry {
+		if existing.Name() == p.Name() {
+			panic(fmt.Sprintf("fetch: provider %q registered twice", p.Name()))
+		}
+	}
+	registry = append(registry, p)
+}
+
+// Lookup returns the provider registered under name, or nil.
+func Lookup(name string) Provider {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, p := range registry {
+		if p.Name() == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// Providers returns the names of all registered providers in registry order.
+func Providers() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, len(registry))
+	for i, p := range registry {
+		names[i] = p.Name()
+	}
+	return names
+}
+
+// candidates returns the providers willing to handle u, most confident first.
+func candidates(u *url.URL) []Provider {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	type candidate struct {
+		provider  Provider
+		detection Detection
+	}
+	var matched []candidate
+	for _, p := range registry {
+		if d := p.Detect(u); d != DetectNo {
+			matched = append(matched, candidate{provider: p, detection: d})
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].detection > matched[j].detection
+	})
+
+	providers := make([]Provider, len(matched))
+	for i, c := range matched {
+		providers[i] = c.provider
+	}
+	return providers
+}
@@ -0,0 +1,486 @@
+package fetch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// providerFixture is a status page recorded as the responses to a provider's
+// requests, and what the provider should make of it.
+type providerFixture struct {
+	name     string
+	provider string
+	url      string
+	// responses maps a request path, with its query where it matters, to
+	// the testdata file it is answered with. Anything else gets a 404.
+	responses map[string]string
+	scope     Scope
+
+	level      StatusLevel
+	label      string
+	note       string                 // part of the ParseNote, if it matters
+	components map[string]StatusLevel // by name
+	groups     map[string]string      // group of each named component
+	// incidents maps titles to impacts, maintenances titles to statuses
+	incidents    map[string]string
+	maintenances map[string]string
+}
+
+var providerFixtures = []providerFixture{
+	{
+		name:      "incidentio partial outage",
+		provider:  "incidentio",
+		url:       "https://status.openai.com",
+		responses: map[string]string{"/proxy/status.openai.com": "incidentio_incident.json"},
+		level:     StatusDegraded,
+		label:     "Partial Outage",
+		components: map[string]StatusLevel{
+			"API":           StatusOperational,
+			"Login":         StatusOperational,
+			"Conversations": StatusDegraded,
+		},
+		groups:       map[string]string{"API": "", "Conversations": "ChatGPT"},
+		incidents:    map[string]string{"Increased error rates in ChatGPT conversations": "minor"},
+		maintenances: map[string]string{"Database maintenance": "maintenance_scheduled"},
+	},
+	{
+		name:      "incidentio full outage",
+		provider:  "incidentio",
+		url:       "https://status.openai.com/proxy/status.openai.com",
+		responses: map[string]string{"/proxy/status.openai.com": "incidentio_outage.json"},
+		level:     StatusMajorDisruption,
+		label:     "Full Outage",
+		components: map[string]StatusLevel{
+			"API":   StatusMajorDisruption,
+			"Login": StatusDegraded,
+		},
+		incidents: map[string]string{"API unavailable": "major", "Slow logins": "minor"},
+	},
+	{
+		name:         "incidentio maintenance",
+		provider:     "incidentio",
+		url:          "https://status.openai.com",
+		responses:    map[string]string{"/proxy/status.openai.com": "incidentio_maintenance.json"},
+		level:        StatusPlannedMaintenance,
+		label:        "Maintenance in Progress",
+		components:   map[string]StatusLevel{"API": StatusPlannedMaintenance},
+		maintenances: map[string]string{"Database maintenance": "maintenance_in_progress"},
+	},
+	{
+		name:      "incidentio rejects other JSON",
+		provider:  "incidentio",
+		url:       "https://status.openai.com",
+		responses: map[string]string{"/proxy/status.openai.com": "statuspage_operational.json"},
+		level:     StatusParseError,
+		note:      "expected an incident.io status page",
+	},
+	{
+		name:      "instatus operational",
+		provider:  "instatus",
+		url:       "https://linearstatus.com",
+		responses: map[string]string{"/summary.json": "instatus_operational.json"},
+		level:     StatusOperational,
+		label:     "All Systems Operational",
+	},
+	{
+		// The page only says it has issues; the worst incident decides how bad
+		name:      "instatus incidents",
+		provider:  "instatus",
+		url:       "https://linearstatus.com",
+		responses: map[string]string{"/summary.json": "instatus_incident.json"},
+		level:     StatusMajorDisruption,
+		label:     "Major Outage",
+		incidents: map[string]string{"Sync delays for some workspaces": "minor", "API unavailable": "major"},
+	},
+	{
+		name:         "instatus maintenance",
+		provider:     "instatus",
+		url:          "https://linearstatus.com/summary.json",
+		responses:    map[string]string{"/summary.json": "instatus_maintenance.json"},
+		level:        StatusPlannedMaintenance,
+		label:        "Under Maintenance",
+		maintenances: map[string]string{"Database upgrade": "in_progress"},
+	},
+	{
+		name:      "instatus rejects other JSON",
+		provider:  "instatus",
+		url:       "https://linearstatus.com",
+		responses: map[string]string{"/summary.json": "betterstack_operational.json"},
+		level:     StatusParseError,
+		note:      "expected an Instatus summary",
+	},
+	{
+		// The ended status report is left out
+		name:      "betterstack operational",
+		provider:  "betterstack",
+		url:       "https://status.betterstack.com",
+		responses: map[string]string{"/index.json": "betterstack_operational.json"},
+		level:     StatusOperational,
+		label:     "All Systems Operational",
+		components: map[string]StatusLevel{
+			"Monitoring": StatusOperational,
+			"Alerting":   StatusOperational,
+		},
+	},
+	{
+		name:      "betterstack downtime",
+		provider:  "betterstack",
+		url:       "https://status.betterstack.com",
+		responses: map[string]string{"/index.json": "betterstack_incident.json"},
+		level:     StatusMajorDisruption,
+		label:     "Downtime",
+		components: map[string]StatusLevel{
+			"Monitoring":    StatusOperational,
+			"Alerting":      StatusDegraded,
+			"Log Ingestion": StatusMajorDisruption,
+		},
+		groups:       map[string]string{"Alerting": "Uptime", "Log Ingestion": "Logs"},
+		incidents:    map[string]string{"Log ingestion outage": "major"},
+		maintenances: map[string]string{"Storage migration": "scheduled"},
+	},
+	{
+		name:         "betterstack maintenance",
+		provider:     "betterstack",
+		url:          "https://status.betterstack.com/index.json",
+		responses:    map[string]string{"/index.json": "betterstack_maintenance.json"},
+		level:        StatusPlannedMaintenance,
+		label:        "Under Maintenance",
+		components:   map[string]StatusLevel{"Log Ingestion": StatusPlannedMaintenance},
+		maintenances: map[string]string{"Storage migration": "in_progress"},
+	},
+	{
+		name:      "betterstack rejects other JSON",
+		provider:  "betterstack",
+		url:       "https://status.betterstack.com",
+		responses: map[string]string{"/index.json": "instatus_operational.json"},
+		level:     StatusParseError,
+		note:      "expected a Better Stack status page",
+	},
+	{
+		name:       "statusio operational",
+		provider:   "statusio",
+		url:        "https://status.io/pages/5d3f1b2c8e4a7b0012a3c4d0",
+		responses:  map[string]string{"/1.0/status/5d3f1b2c8e4a7b0012a3c4d0": "statusio_operational.json"},
+		level:      StatusOperational,
+		label:      "All Systems Operational",
+		components: map[string]StatusLevel{"Website": StatusOperational},
+	},
+	{
+		// API is reported per region, and the incident takes the page's status
+		name:      "statusio disruption",
+		provider:  "statusio",
+		url:       "https://api.status.io/1.0/status/5d3f1b2c8e4a7b0012a3c4d0",
+		responses: map[string]string{"/1.0/status/5d3f1b2c8e4a7b0012a3c4d0": "statusio_incident.json"},
+		level:     StatusDegraded,
+		label:     "Partial Service Disruption",
+		components: map[string]StatusLevel{
+			"Website": StatusOperational,
+			"US East": StatusDegraded,
+			"EU West": StatusOperational,
+		},
+		groups:       map[string]string{"Website": "", "US East": "API"},
+		incidents:    map[string]string{"Elevated API error rates in US East": "minor"},
+		maintenances: map[string]string{"Network upgrade": "scheduled"},
+	},
+	{
+		name:         "statusio maintenance",
+		provider:     "statusio",
+		url:          "https://status.io/pages/5d3f1b2c8e4a7b0012a3c4d0",
+		responses:    map[string]string{"/1.0/status/5d3f1b2c8e4a7b0012a3c4d0": "statusio_maintenance.json"},
+		level:        StatusPlannedMaintenance,
+		label:        "Planned Maintenance",
+		components:   map[string]StatusLevel{"Website": StatusPlannedMaintenance},
+		maintenances: map[string]string{"Network upgrade": "in_progress"},
+	},
+	{
+		name:      "statusio rejects other JSON",
+		provider:  "statusio",
+		url:       "https://status.io/pages/5d3f1b2c8e4a7b0012a3c4d0",
+		responses: map[string]string{"/1.0/status/5d3f1b2c8e4a7b0012a3c4d0": "statuspage_operational.json"},
+		level:     StatusParseError,
+		note:      "expected a Status.io status response",
+	},
+	{
+		// An incident takes the status of its component; Legacy FTP is
+		// disabled, and Mail's status is named from its number
+		name:     "cachet",
+		provider: "cachet",
+		url:      "https://status.example.com/api/v1/components",
+		responses: map[string]string{
+			"/api/v1/components":                      "cachet_components.json",
+			"/api/v1/components/groups":               "cachet_groups.json",
+			"/api/v1/incidents?per_page=100&status=0": "cachet_scheduled.json",
+			"/api/v1/incidents?per_page=100&status=2": "cachet_identified.json",
+			"/api/v1/incidents":                       "cachet_no_incidents.json",
+		},
+		level: StatusDegraded,
+		label: "Partial Outage",
+		components: map[string]StatusLevel{
+			"Website": StatusOperational,
+			"API":     StatusDegraded,
+			"Mail":    StatusDegraded,
+		},
+		groups:       map[string]string{"Website": "Core", "API": "Core", "Mail": "Messaging"},
+		incidents:    map[string]string{"API errors in the EU": "minor"},
+		maintenances: map[string]string{"Mail server upgrade": "scheduled"},
+	},
+	{
+		// Older versions send every incident whatever the status asked for
+		name:     "cachet without status filter",
+		provider: "cachet",
+		url:      "https://status.example.com/api/v1/components",
+		responses: map[string]string{
+			"/api/v1/components":        "cachet_components.json",
+			"/api/v1/components/groups": "cachet_groups.json",
+			"/api/v1/incidents":         "cachet_all_incidents.json",
+		},
+		level: StatusDegraded,
+		label: "Partial Outage",
+		components: map[string]StatusLevel{
+			"Website": StatusOperational,
+			"API":     StatusDegraded,
+			"Mail":    StatusDegraded,
+		},
+		incidents:    map[string]string{"API errors in the EU": "minor"},
+		maintenances: map[string]string{"Mail server upgrade": "scheduled"},
+	},
+	{
+		name:     "cachet without groups",
+		provider: "cachet",
+		url:      "https://status.example.com/api/v1/components",
+		responses: map[string]string{
+			"/api/v1/components": "cachet_components.json",
+			"/api/v1/incidents":  "cachet_no_incidents.json",
+		},
+		level: StatusDegraded,
+		label: "Partial Outage",
+		note:  "components ungrouped",
+		components: map[string]StatusLevel{
+			"Website": StatusOperational,
+			"API":     StatusDegraded,
+			"Mail":    StatusDegraded,
+		},
+		groups: map[string]string{"API": "", "Mail": ""},
+	},
+	{
+		name:      "cachet rejects other JSON",
+		provider:  "cachet",
+		url:       "https://status.example.com/api/v1/components",
+		responses: map[string]string{"/api/v1/components": "instatus_operational.json"},
+		level:     StatusParseError,
+		note:      "expected a Cachet component list",
+	},
+	{
+		// Each monitor's latest heartbeat decides its level; the pinned
+		// incident's warning style makes it minor
+		name:     "uptimekuma",
+		provider: "uptimekuma",
+		url:      "https://kuma.example.com/status/main",
+		responses: map[string]string{
+			"/api/status-page/main":           "uptimekuma_page.json",
+			"/api/status-page/heartbeat/main": "uptimekuma_heartbeat.json",
+		},
+		level: StatusMajorDisruption,
+		label: "2 Monitors Down",
+		components: map[string]StatusLevel{
+			"Homepage": StatusOperational,
+			"Checkout": StatusDegraded,
+			"Database": StatusMajorDisruption,
+			"Queue":    StatusMajorDisruption,
+			"Search":   StatusPlannedMaintenance,
+		},
+		groups:       map[string]string{"Homepage": "Web", "Database": "Backend"},
+		incidents:    map[string]string{"Payment provider degraded": "minor"},
+		maintenances: map[string]string{"Search reindex": "in_progress"},
+	},
+	{
+		name:      "uptimekuma rejects other JSON",
+		provider:  "uptimekuma",
+		url:       "https://kuma.example.com/status/main",
+		responses: map[string]string{"/api/status-page/main": "statuspage_operational.json"},
+		level:     StatusParseError,
+		note:      "expected an Uptime Kuma status page",
+	},
+	{
+		// docs has no results yet
+		name:      "gatus unhealthy endpoint",
+		provider:  "gatus",
+		url:       "https://status.example.org",
+		responses: map[string]string{"/api/v1/endpoints/statuses": "gatus_statuses.json"},
+		level:     StatusMajorDisruption,
+		label:     "1 Endpoint Unhealthy",
+		components: map[string]StatusLevel{
+			"frontend": StatusOperational,
+			"api":      StatusMajorDisruption,
+			"docs":     StatusUnknown,
+		},
+		groups: map[string]string{"frontend": "core", "docs": ""},
+	},
+	{
+		name:       "gatus healthy",
+		provider:   "gatus",
+		url:        "https://status.example.org/api/v1/endpoints/statuses",
+		responses:  map[string]string{"/api/v1/endpoints/statuses": "gatus_healthy.json"},
+		level:      StatusOperational,
+		label:      "All Endpoints Healthy",
+		components: map[string]StatusLevel{"frontend": StatusOperational},
+	},
+	{
+		name:      "gatus rejects other JSON",
+		provider:  "gatus",
+		url:       "https://status.example.org",
+		responses: map[string]string{"/api/v1/endpoints/statuses": "upptime_summary.json"},
+		level:     StatusParseError,
+		note:      "expected Gatus endpoint statuses",
+	},
+	{
+		name:      "upptime repository",
+		provider:  "upptime",
+		url:       "https://github.com/upptime/upptime",
+		responses: map[string]string{"/upptime/upptime/HEAD/history/summary.json": "upptime_summary.json"},
+		level:     StatusMajorDisruption,
+		label:     "1 Site Down",
+		components: map[string]StatusLevel{
+			"Google":      StatusOperational,
+			"Wikipedia":   StatusDegraded,
+			"Hacker News": StatusMajorDisruption,
+		},
+	},
+	{
+		name:      "upptime rejects other JSON",
+		provider:  "upptime",
+		url:       "https://upptime.github.io/upptime",
+		responses: map[string]string{"/upptime/upptime/HEAD/history/summary.json": "gatus_healthy.json"},
+		level:     StatusParseError,
+		note:      "expected an Upptime summary",
+	},
+	{
+		// The resolved Cloud Storage incident is left out
+		name:      "gcp",
+		provider:  "gcp",
+		url:       "https://status.cloud.google.com",
+		responses: map[string]string{"/incidents.json": "gcp_incidents.json"},
+		level:     StatusMajorDisruption,
+		label:     "2 Open Incidents",
+		incidents: map[string]string{
+			"Google Compute Engine instances unreachable in us-central1": "major",
+			"Elevated BigQuery query latency":                            "minor",
+		},
+	},
+	{
+		// The BigQuery incident lists no locations, so it counts everywhere
+		name:      "gcp scoped",
+		provider:  "gcp",
+		url:       "https://status.cloud.google.com",
+		responses: map[string]string{"/incidents.json": "gcp_incidents.json"},
+		scope:     Scope{Products: []string{"*BigQuery*"}, Regions: []string{"europe-west1"}},
+		level:     StatusDegraded,
+		label:     "Elevated BigQuery query latency",
+		incidents: map[string]string{"Elevated BigQuery query latency": "minor"},
+	},
+	{
+		name:      "gcp rejects other JSON",
+		provider:  "gcp",
+		url:       "https://status.cloud.google.com",
+		responses: map[string]string{"/incidents.json": "upptime_summary.json"},
+		level:     StatusParseError,
+		note:      "expected Google Cloud incidents",
+	},
+}
+
+// fixtureTransport sends every request, whatever its host, to srv.
+type fixtureTransport struct {
+	srv *httptest.Server
+}
+
+func (t fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	target, _ := url.Parse(t.srv.URL)
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = target.Scheme, target.Host
+	return t.srv.Client().Transport.RoundTrip(req)
+}
+
+// fetchFixture serves tt's responses and fetches tt.url through its
+// provider.
+func fetchFixture(t *testing.T, tt providerFixture) *Result {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := tt.responses[r.URL.RequestURI()]
+		if !ok {
+			name, ok = tt.responses[r.URL.Path]
+		}
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		body, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Error(err)
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient()
+	c.http.Transport = fixtureTransport{srv: srv}
+	result, err := c.Fetch(context.Background(), Target{URL: tt.url, Provider: tt.provider, Scope: tt.scope})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestProviderFixtures(t *testing.T) {
+	for _, tt := range providerFixtures {
+		t.Run(tt.name, func(t *testing.T) {
+			result := fetchFixture(t, tt)
+			if result.Level != tt.level || result.Label != tt.label {
+				t.Errorf("got %v %q, want %v %q (%s)", result.Level, result.Label, tt.level, tt.label, result.ParseNote)
+			}
+			if !strings.Contains(result.ParseNote, tt.note) {
+				t.Errorf("got note %q, want it to contain %q", result.ParseNote, tt.note)
+			}
+
+			levels := componentLevels(result.Components)
+			if len(levels) != len(tt.components) {
+				t.Errorf("got %d components, want %d", len(levels), len(tt.components))
+			}
+			for name, level := range tt.components {
+				if got, ok := levels[name]; !ok || got != level {
+					t.Errorf("component %q: got %v, want %v", name, got, level)
+				}
+			}
+			for _, c := range result.Components {
+				if group, ok := tt.groups[c.Name]; ok && c.Group != group {
+					t.Errorf("component %q: got group %q, want %q", c.Name, c.Group, group)
+				}
+			}
+
+			if len(result.Incidents) != len(tt.incidents) {
+				t.Errorf("got %d incidents, want %d", len(result.Incidents), len(tt.incidents))
+			}
+			for _, inc := range result.Incidents {
+				if impact, ok := tt.incidents[inc.Title]; !ok || inc.Impact != impact {
+					t.Errorf("incident %q: got impact %q, want %q", inc.Title, inc.Impact, impact)
+				}
+			}
+			if len(result.Maintenances) != len(tt.maintenances) {
+				t.Errorf("got %d maintenances, want %d", len(result.Maintenances), len(tt.maintenances))
+			}
+			for _, m := range result.Maintenances {
+				if status, ok := tt.maintenances[m.Title]; !ok || m.Status != status {
+					t.Errorf("maintenance %q: got status %q, want %q", m.Title, m.Status, status)
+				}
+			}
+		})
+	}
+}
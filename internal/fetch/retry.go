@@ -0,0 +1,249 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryPolicy controls how requests are retried after transient failures:
+// transport errors, HTTP 429 and 5xx gateway errors.
+type RetryPolicy struct {
+	// MaxAttempts includes the first request; 1 disables retries.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	// MaxDelay caps the backoff. A Retry-After longer than this isn't
+	// waited for; the host's circuit is opened until then instead.
+	MaxDelay time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// backoff returns the delay before retry number attempt (from 1), using
+// exponential backoff with jitter so many services don't retry in lockstep.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay << (attempt - 1)
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// StatusError is returned for a non-200 response.
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("HTTP %d (retry after %s)", e.StatusCode, e.RetryAfter)
+	}
+	return fmt.Sprintf("HTTP %d", e.StatusCode)
+}
+
+// transient reports whether a retry might succeed. Any response other than
+// these means the server is up and answered deliberately.
+func (e *StatusError) transient() bool {
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryable reports whether err is worth retrying, and so also counts
+// against the host's circuit breaker.
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.transient()
+	}
+	var openErr *CircuitOpenError
+	var sizeErr *TooLargeError
+	return !errors.As(err, &openErr) && !errors.As(err, &sizeErr)
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP
+// date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// CircuitOpenError is returned without making a request while a host's
+// circuit is open.
+type CircuitOpenError struct {
+	Host  string
+	Until time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("backing off from %s until %s", e.Host, e.Until.Format("15:04:05"))
+}
+
+// breaker is a per-host circuit breaker. After threshold consecutive checks
+// that failed to reach a host, or when it rate-limits us, requests to it fail
+// fast until the cooldown (or Retry-After) has passed. The next request is
+// let through as a probe; if it fails the circuit opens again.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+}
+
+type hostState struct {
+	failures  int
+	openUntil time.Time
+}
+
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	return &breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		hosts:     make(map[string]*hostState),
+	}
+}
+
+// allow returns a CircuitOpenError if the host's circuit is open.
+func (b *breaker) allow(host string, now time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if state := b.hosts[host]; state != nil && now.Before(state.openUntil) {
+		return &CircuitOpenError{Host: host, Until: state.openUntil}
+	}
+	return nil
+}
+
+// record updates the host's circuit after a check whose requests to it ended
+// with err.
+func (b *breaker) record(host string, err error, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		delete(b.hosts, host)
+		return
+	}
+	// The host answered, just not with what we wanted
+	if !retryable(err) {
+		return
+	}
+
+	state := b.hosts[host]
+	if state == nil {
+		state = &hostState{}
+		b.hosts[host] = state
+	}
+	state.failures++
+
+	var until time.Time
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusTooManyRequests {
+		// Rate limited: back off at once rather than after threshold failures
+		until = now.Add(max(statusErr.RetryAfter, b.cooldown))
+	} else if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+		until = now.Add(statusErr.RetryAfter)
+	}
+	if b.threshold > 0 && state.failures >= b.threshold {
+		until = later(until, now.Add(b.cooldown))
+	}
+	state.openUntil = later(state.openUntil, until)
+}
+
+// checkTally collects the outcome of every request one Client.Fetch makes,
+// so that a check counts once against each host's circuit however many
+// probes and retries it took.
+type checkTally struct {
+	mu    sync.Mutex
+	hosts map[string]*hostTally
+}
+
+type hostTally struct {
+	reached bool  // a request succeeded
+	err     error // the failure to record otherwise
+}
+
+type tallyKey struct{}
+
+// withTally returns a context whose requests are tallied instead of being
+// recorded against the breaker one by one.
+func withTally(ctx context.Context) (context.Context, *checkTally) {
+	t := &checkTally{hosts: make(map[string]*hostTally)}
+	return context.WithValue(ctx, tallyKey{}, t), t
+}
+
+// recordRequest notes the outcome of one request to host: in the check's
+// tally when there is one, otherwise straight on the breaker.
+func (c *Client) recordRequest(ctx context.Context, host string, err error) {
+	t, _ := ctx.Value(tallyKey{}).(*checkTally)
+	if t == nil {
+		c.breaker.record(host, err, time.Now())
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	h := t.hosts[host]
+	if h == nil {
+		h = &hostTally{}
+		t.hosts[host] = h
+	}
+	switch {
+	case err == nil:
+		h.reached = true
+	case !retryable(err):
+	case h.err == nil || backsOff(err):
+		// A rate limit says more than whatever else failed
+		h.err = err
+	}
+}
+
+// flush records one outcome per host on b.
+func (t *checkTally) flush(b *breaker, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for host, h := range t.hosts {
+		switch {
+		case h.reached:
+			b.record(host, nil, now)
+		case h.err != nil:
+			b.record(host, h.err, now)
+		}
+	}
+}
+
+// backsOff reports whether err asks us to back off: HTTP 429 or a
+// Retry-After.
+func backsOff(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && (statusErr.StatusCode == http.StatusTooManyRequests || statusErr.RetryAfter > 0)
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
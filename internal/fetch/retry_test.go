@@ -0,0 +1,81 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBreakerCountsChecks(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	policy := RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	c := NewClientWithPolicy(policy, 2, time.Minute)
+	// Unpinned, so detection probes several paths on the host
+	target := Target{URL: srv.URL + "/status"}
+
+	result, _ := c.Fetch(context.Background(), target)
+	if requests.Load() < 2 {
+		t.Fatalf("made %d requests, want several", requests.Load())
+	}
+	if strings.Contains(result.ParseNote, "backing off") {
+		t.Fatalf("first check opened the circuit: %s", result.ParseNote)
+	}
+
+	result, _ = c.Fetch(context.Background(), target)
+	if result.Level != StatusConnectionError || strings.Contains(result.ParseNote, "backing off") {
+		t.Fatalf("second check: got %v %q, want the host's own error", result.Level, result.ParseNote)
+	}
+
+	made := requests.Load()
+	result, _ = c.Fetch(context.Background(), target)
+	if !strings.Contains(result.ParseNote, "backing off") || requests.Load() != made {
+		t.Errorf("third check: got %q after %d new requests, want the circuit open", result.ParseNote, requests.Load()-made)
+	}
+}
+
+func TestBreakerResetsOnSuccess(t *testing.T) {
+	b := newBreaker(2, time.Minute)
+	now := time.Now()
+	down := &StatusError{StatusCode: http.StatusBadGateway}
+
+	b.record("example.com", down, now)
+	b.record("example.com", nil, now)
+	b.record("example.com", down, now)
+	if err := b.allow("example.com", now); err != nil {
+		t.Fatalf("failures weren't consecutive, but got %v", err)
+	}
+
+	b.record("example.com", down, now)
+	var openErr *CircuitOpenError
+	if err := b.allow("example.com", now); !errors.As(err, &openErr) {
+		t.Fatalf("got %v, want the circuit open", err)
+	}
+	if err := b.allow("example.com", now.Add(2*time.Minute)); err != nil {
+		t.Errorf("got %v after the cooldown, want a probe let through", err)
+	}
+}
+
+func TestBreakerRateLimit(t *testing.T) {
+	b := newBreaker(5, time.Minute)
+	now := time.Now()
+	b.record("example.com", &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: 5 * time.Minute}, now)
+
+	var openErr *CircuitOpenError
+	if err := b.allow("example.com", now.Add(2*time.Minute)); !errors.As(err, &openErr) {
+		t.Fatalf("got %v, want the circuit open until Retry-After", err)
+	}
+	if err := b.allow("example.com", now.Add(6*time.Minute)); err != nil {
+		t.Errorf("got %v after Retry-After", err)
+	}
+}
@@ -0,0 +1,215 @@
+package fetch
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	Description string `xml:"description"`
+	Link        string `xml:"link"`
+	PubDate     string `xml:"pubDate"`
+}
+
+type rssFeed struct {
+	Channel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type atomEntry struct {
+	Title   string `xml:"title"`
+	Summary string `xml:"summary"`
+	Updated string `xml:"updated"`
+	Link    struct {
+		Href string `xml:"href,attr"`
+	} `xml:"link"`
+}
+
+type atomFeed struct {
+	Title   string      `xml:"title"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+// rssProvider reads RSS 2.0 and Atom incident feeds.
+type rssProvider struct{}
+
+func (rssProvider) Name() string { return "rss" }
+
+func (rssProvider) Detect(u *url.URL) Detection {
+	if strings.HasSuffix(u.Path, ".rss") || strings.HasSuffix(u.Path, ".xml") ||
+		strings.Contains(u.Path, "/rss") || strings.Contains(u.Path, "/feed") ||
+		strings.Contains(u.Path, "/atom") {
+		return DetectYes
+	}
+	return DetectNo
+}
+
+func (p rssProvider) Fetch(ctx context.Context, c *Client, u *url.URL) (*Result, error) {
+	urlStr := u.String()
+
+	result, err := c.getResult(ctx, p.Name(), urlStr, "application/rss+xml, application/atom+xml, application/xml, text/xml", expect(contentXML, parseFeed))
+	if err != nil {
+		return nil, err
+	}
+	result.SourceURL = urlStr
+	return result, nil
+}
+
+func parseFeed(body []byte) (*Result, error) {
+	result := &Result{
+		CheckedAt: time.Now(),
+		ParseNote: "Parsed RSS/Atom feed",
+	}
+
+	// Try RSS first
+	var rss rssFeed
+	if err := xml.Unmarshal(body, &rss); err == nil && len(rss.Channel.Items) > 0 {
+		return parseRSSItems(rss.Channel.Items, result), nil
+	}
+
+	// Try Atom
+	var atom atomFeed
+	if err := xml.Unmarshal(body, &atom); err == nil && len(atom.Entries) > 0 {
+		return parseAtomEntries(atom.Entries, result), nil
+	}
+
+	return nil, fmt.Errorf("failed to parse as RSS or Atom feed")
+}
+
+func parseRSSItems(items []rssItem, result *Result) *Result {
+	if len(items) == 0 {
+		result.Level = StatusUnknown
+		result.Label = "No feed items found"
+		return result
+	}
+
+	// Status is determined by the MOST RECENT item only
+	latestItem := items[0]
+	latestLower := strings.ToLower(latestItem.Title + " " + latestItem.Description)
+	var currentStatus StatusLevel = StatusOperational
+	var currentLabel string = "All Systems Operational"
+
+	// Check if latest item indicates everything is OK
+	if strings.Contains(latestLower, "resolved") || strings.Contains(latestLower, "operating normally") {
+		currentStatus = StatusOperational
+		currentLabel = "All Systems Operational"
+	} else if strings.Contains(latestLower, "major") || strings.Contains(latestLower, "outage") || strings.Contains(latestLower, "disruption") {
+		currentStatus = StatusMajorDisruption
+		currentLabel = latestItem.Title
+	} else if strings.Contains(latestLower, "degraded") || strings.Contains(latestLower, "degradation") ||
+		strings.Contains(latestLower, "impact") || strings.Contains(latestLower, "latenc") ||
+		strings.Contains(latestLower, "error") {
+		currentStatus = StatusDegraded
+		currentLabel = latestItem.Title
+	} else if strings.Contains(latestLower, "maintenance") || strings.Contains(latestLower, "scheduled") {
+		currentStatus = StatusPlannedMaintenance
+		currentLabel = latestItem.Title
+	}
+
+	// Collect all incidents from last 7 days for history
+	sevenDaysAgo := time.Now().AddDate(0, 0, -7)
+	var recentIncidents []Incident
+
+	for _, item := range items {
+		// Parse pubDate (RSS format: "Mon, 20 Oct 2025 15:53:00 PDT")
+		pubDate, err := time.Parse("Mon, 02 Jan 2006 15:04:05 MST", item.PubDate)
+		if err != nil {
+			// If parse fails, include it anyway (assume recent)
+			pubDate = time.Now()
+		}
+
+		// Skip items older than 7 days
+		if pubDate.Before(sevenDaysAgo) {
+			continue
+		}
+
+		titleLower := strings.ToLower(item.Title)
+		descLower := strings.ToLower(item.Description)
+		combined := titleLower + " " + descLower
+
+		// Determine if this is an incident or maintenance
+		if strings.Contains(combined, "maintenance") || strings.Contains(combined, "scheduled") {
+			// Skip maintenance items for incident list
+			continue
+		}
+
+		// Check if it's resolved - do this FIRST
+		var resolvedAt *time.Time
+		itemStatus := "investigating"
+		isResolved := strings.Contains(combined, "resolved") || strings.Contains(combined, "operating normally")
+		if isResolved {
+			resolvedAt = &pubDate
+			itemStatus = "resolved"
+		}
+
+		// Determine impact level for incident history
+		impact := "minor"
+		if strings.Contains(combined, "major") || strings.Contains(combined, "outage") || strings.Contains(combined, "disruption") {
+			impact = "major"
+		} else if strings.Contains(combined, "degraded") || strings.Contains(combined, "degradation") ||
+			strings.Contains(combined, "impact") || strings.Contains(combined, "latenc") ||
+			strings.Contains(combined, "error") {
+			impact = "minor"
+		} else if !isResolved {
+			// Unrecognized non-resolved item, skip it
+			continue
+		}
+
+		// Add to incidents list
+		recentIncidents = append(recentIncidents, Incident{
+			ID:         item.Link,
+			Title:      item.Title,
+			URL:        item.Link,
+			Status:     itemStatus,
+			Impact:     impact,
+			StartedAt:  pubDate,
+			UpdatedAt:  pubDate,
+			ResolvedAt: resolvedAt,
+		})
+	}
+
+	result.Level = currentStatus
+	result.Label = currentLabel
+	result.Incidents = recentIncidents
+
+	// Add maintenance info if latest item is about maintenance
+	if currentStatus == StatusPlannedMaintenance {
+		pubDate, _ := time.Parse("Mon, 02 Jan 2006 15:04:05 MST", latestItem.PubDate)
+		if pubDate.IsZero() {
+			pubDate = time.Now()
+		}
+		result.Maintenances = []Maintenance{{
+			ID:      latestItem.Link,
+			Title:   latestItem.Title,
+			Status:  "scheduled",
+			StartAt: pubDate,
+			EndAt:   pubDate.Add(24 * time.Hour),
+		}}
+	}
+
+	return result
+}
+
+func parseAtomEntries(entries []atomEntry, result *Result) *Result {
+	if len(entries) == 0 {
+		result.Level = StatusUnknown
+		result.Label = "No feed entries found"
+		return result
+	}
+
+	// Convert first entry to RSS format and reuse logic
+	item := rssItem{
+		Title:       entries[0].Title,
+		Description: entries[0].Summary,
+		Link:        entries[0].Link.Href,
+		PubDate:     entries[0].Updated,
+	}
+	return parseRSSItems([]rssItem{item}, result)
+}
@@ -0,0 +1,37 @@
+package fetch
+
+import "testing"
+
+func TestParseFeed(t *testing.T) {
+	tests := []struct {
+		fixture      string
+		level        StatusLevel
+		label        string
+		maintenances int
+	}{
+		{"rss_incident.xml", StatusDegraded, "Increased API Error Rates", 0},
+		{"rss_resolved.xml", StatusOperational, "All Systems Operational", 0},
+		{"atom_maintenance.xml", StatusPlannedMaintenance, "Scheduled maintenance of the database cluster", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			result, err := parseFeed(loadFixture(t, tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Level != tt.level || result.Label != tt.label {
+				t.Errorf("got %v %q, want %v %q", result.Level, result.Label, tt.level, tt.label)
+			}
+			if len(result.Maintenances) != tt.maintenances {
+				t.Errorf("got %d maintenances, want %d", len(result.Maintenances), tt.maintenances)
+			}
+		})
+	}
+}
+
+func TestParseFeedRejectsOtherXML(t *testing.T) {
+	if _, err := parseFeed([]byte(`<?xml version="1.0"?><html><body>Not a feed</body></html>`)); err == nil {
+		t.Error("got nil error for XHTML")
+	}
+}
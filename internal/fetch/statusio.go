@@ -0,0 +1,198 @@
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	statusioAPIHost    = "api.status.io"
+	statusioStatusPath = "/1.0/status/"
+)
+
+type statusioResponse struct {
+	Result *struct {
+		StatusOverall struct {
+			Status     string `json:"status"`
+			StatusCode int    `json:"status_code"`
+		} `json:"status_overall"`
+		Status []struct {
+			ID         string `json:"id"`
+			Name       string `json:"name"`
+			Status     string `json:"status"`
+			StatusCode int    `json:"status_code"`
+			Containers []struct {
+				ID         string `json:"id"`
+				Name       string `json:"name"`
+				Status     string `json:"status"`
+				StatusCode int    `json:"status_code"`
+			} `json:"containers"`
+		} `json:"status"`
+		Incidents []struct {
+			ID           string    `json:"_id"`
+			Name         string    `json:"name"`
+			DatetimeOpen time.Time `json:"datetime_open"`
+		} `json:"incidents"`
+		Maintenance struct {
+			Active   []statusioMaintenance `json:"active"`
+			Upcoming []statusioMaintenance `json:"upcoming"`
+		} `json:"maintenance"`
+	} `json:"result"`
+}
+
+type statusioMaintenance struct {
+	ID    string    `json:"_id"`
+	Name  string    `json:"name"`
+	Start time.Time `json:"datetime_planned_start"`
+	End   time.Time `json:"datetime_planned_end"`
+}
+
+// statusioProvider reads the Status.io public status API. That API is keyed
+// by page ID rather than hostname, so it is only used for status.io/pages/ID
+// links and api.status.io URLs; pages on custom domains need the API URL
+// configured instead.
+type statusioProvider struct{}
+
+func (statusioProvider) Name() string { return "statusio" }
+
+func (statusioProvider) Detect(u *url.URL) Detection {
+	if statusioPageID(u) != "" {
+		return DetectYes
+	}
+	return DetectNo
+}
+
+func (p statusioProvider) Fetch(ctx context.Context, c *Client, u *url.URL) (*Result, error) {
+	pageID := statusioPageID(u)
+	if pageID == "" {
+		return nil, fmt.Errorf("no Status.io page ID in %s", u)
+	}
+	apiURL := url.URL{Scheme: "https", Host: statusioAPIHost, Path: statusioStatusPath + pageID}
+	urlStr := apiURL.String()
+	pageURL := "https://status.io/pages/" + pageID
+
+	result, err := c.getResult(ctx, p.Name(), urlStr, "application/json", expect(contentJSON, func(body []byte) (*Result, error) {
+		return parseStatusio(body, pageURL)
+	}))
+	if err != nil {
+		return nil, err
+	}
+	result.SourceURL = urlStr
+	return result, nil
+}
+
+// statusioPageID finds the page ID in an API URL or a status.io/pages/ link.
+func statusioPageID(u *url.URL) string {
+	host := u.Hostname()
+	switch {
+	case host == statusioAPIHost && strings.HasPrefix(u.Path, statusioStatusPath):
+	case (host == "status.io" || host == "www.status.io") && strings.HasPrefix(u.Path, "/pages/"):
+	default:
+		return ""
+	}
+	id := path.Base(strings.TrimSuffix(u.Path, "/"))
+	if id == "pages" || id == "status" {
+		return ""
+	}
+	return id
+}
+
+func parseStatusio(body []byte, pageURL string) (*Result, error) {
+	var resp statusioResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Result == nil {
+		return nil, &ContentTypeError{Want: "a Status.io status response", Got: "other JSON"}
+	}
+	r := resp.Result
+
+	result := &Result{
+		CheckedAt: time.Now(),
+		Level:     statusioLevel(r.StatusOverall.StatusCode),
+		Label:     r.StatusOverall.Status,
+		ParseNote: "Parsed Status.io API",
+	}
+	if result.Level == StatusOperational {
+		result.Label = "All Systems Operational"
+	}
+
+	// Components are reported per container (e.g. region) where the page
+	// has them
+	for _, comp := range r.Status {
+		if len(comp.Containers) == 0 {
+			result.Components = append(result.Components, Component{
+				ID:     comp.ID,
+				Name:   comp.Name,
+				Status: comp.Status,
+				Level:  statusioLevel(comp.StatusCode),
+			})
+			continue
+		}
+		for _, container := range comp.Containers {
+			result.Components = append(result.Components, Component{
+				ID:     comp.ID + "/" + container.ID,
+				Name:   container.Name,
+				Status: container.Status,
+				Group:  comp.Name,
+				Level:  statusioLevel(container.StatusCode),
+			})
+		}
+	}
+
+	// Incidents carry no impact of their own, so they take the page's overall
+	// status
+	for _, inc := range r.Incidents {
+		result.Incidents = append(result.Incidents, Incident{
+			ID:        inc.ID,
+			Title:     inc.Name,
+			URL:       pageURL,
+			Status:    "ongoing",
+			Impact:    levelImpact(result.Level),
+			StartedAt: inc.DatetimeOpen,
+			UpdatedAt: inc.DatetimeOpen,
+		})
+	}
+
+	addMaintenance := func(maint statusioMaintenance, status string) {
+		result.Maintenances = append(result.Maintenances, Maintenance{
+			ID:      maint.ID,
+			Title:   maint.Name,
+			Status:  status,
+			Impact:  impactMaintenance,
+			StartAt: maint.Start,
+			EndAt:   maint.End,
+		})
+	}
+	for _, maint := range r.Maintenance.Active {
+		addMaintenance(maint, "in_progress")
+	}
+	for _, maint := range r.Maintenance.Upcoming {
+		addMaintenance(maint, "scheduled")
+	}
+
+	return result, nil
+}
+
+// statusioLevel maps Status.io status codes: 100 operational, 200 planned
+// maintenance, 300 degraded performance, 400 partial service disruption, 500
+// service disruption and 600 security event.
+func statusioLevel(code int) StatusLevel {
+	switch code {
+	case 100:
+		return StatusOperational
+	case 200:
+		return StatusPlannedMaintenance
+	case 300, 400:
+		return StatusDegraded
+	case 500, 600:
+		return StatusMajorDisruption
+	default:
+		return StatusUnknown
+	}
+}
@@ -0,0 +1,193 @@
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const statuspageSummaryPath = "/api/v2/summary.json"
+
+type statuspageResponse struct {
+	Page struct {
+		Name      string    `json:"name"`
+		UpdatedAt time.Time `json:"updated_at"`
+	} `json:"page"`
+	Status struct {
+		Indicator   string `json:"indicator"`
+		Description string `json:"description"`
+	} `json:"status"`
+	Components            []statuspageComponent `json:"components"`
+	Incidents             []Incident            `json:"incidents"`
+	ScheduledMaintenances []Maintenance         `json:"scheduled_maintenances"`
+}
+
+// statuspageAffected picks the affected components out of incidents and
+// maintenances, which the shared Incident and Maintenance types don't decode.
+type statuspageAffected struct {
+	Incidents []struct {
+		Components []statuspageComponent `json:"components"`
+	} `json:"incidents"`
+	ScheduledMaintenances []struct {
+		Components []statuspageComponent `json:"components"`
+	} `json:"scheduled_maintenances"`
+}
+
+type statuspageComponent struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	GroupID string `json:"group_id"`
+	Group   bool   `json:"group"`
+}
+
+// statuspageProvider reads the Statuspage.io summary API.
+type statuspageProvider struct{}
+
+func (statuspageProvider) Name() string { return "statuspage" }
+
+func (statuspageProvider) Detect(u *url.URL) Detection {
+	if strings.Contains(u.Path, statuspageSummaryPath) || strings.HasSuffix(u.Hostname(), ".statuspage.io") {
+		return DetectYes
+	}
+	return DetectProbe
+}
+
+func (p statuspageProvider) Fetch(ctx context.Context, c *Client, u *url.URL) (*Result, error) {
+	apiURL := *u
+	if !strings.Contains(apiURL.Path, statuspageSummaryPath) {
+		apiURL.Path = statuspageSummaryPath
+	}
+	urlStr := apiURL.String()
+
+	result, err := c.getResult(ctx, p.Name(), urlStr, "application/json", expect(contentJSON, parseStatuspage))
+	if err != nil {
+		return nil, err
+	}
+	result.SourceURL = urlStr
+	return result, nil
+}
+
+func parseStatuspage(body []byte) (*Result, error) {
+	var spResp statuspageResponse
+	if err := json.Unmarshal(body, &spResp); err != nil {
+		return nil, err
+	}
+	// Any JSON decodes; without an indicator this is some other API, and
+	// accepting it would end detection on the wrong provider
+	if spResp.Status.Indicator == "" {
+		return nil, errNotStatuspage
+	}
+
+	var affected statuspageAffected
+	if err := json.Unmarshal(body, &affected); err != nil {
+		return nil, err
+	}
+	for i := range spResp.Incidents {
+		spResp.Incidents[i].Components = componentNames(affected.Incidents[i].Components)
+	}
+	for i := range spResp.ScheduledMaintenances {
+		spResp.ScheduledMaintenances[i].Components = componentNames(affected.ScheduledMaintenances[i].Components)
+	}
+
+	result := &Result{
+		CheckedAt:    time.Now(),
+		Incidents:    spResp.Incidents,
+		Maintenances: spResp.ScheduledMaintenances,
+		Components:   statuspageComponents(spResp.Components),
+		ParseNote:    "Parsed Statuspage.io JSON API",
+	}
+
+	for i := range result.Incidents {
+		if len(result.Incidents[i].Updates) > 0 {
+			result.Incidents[i].StartedAt = result.Incidents[i].Updates[len(result.Incidents[i].Updates)-1].CreatedAt
+			result.Incidents[i].UpdatedAt = result.Incidents[i].Updates[0].CreatedAt
+		}
+		if result.Incidents[i].Status == "resolved" || result.Incidents[i].Status == "completed" {
+			t := result.Incidents[i].UpdatedAt
+			result.Incidents[i].ResolvedAt = &t
+		}
+	}
+
+	switch strings.ToLower(spResp.Status.Indicator) {
+	case "none":
+		result.Level = StatusOperational
+		result.Label = "All Systems Operational"
+	case "minor":
+		result.Level = StatusDegraded
+		result.Label = spResp.Status.Description
+	case "major":
+		result.Level = StatusMajorDisruption
+		result.Label = spResp.Status.Description
+	case "critical":
+		result.Level = StatusMajorDisruption
+		result.Label = spResp.Status.Description
+	case "maintenance":
+		result.Level = StatusPlannedMaintenance
+		result.Label = spResp.Status.Description
+	default:
+		result.Level = StatusUnknown
+		result.Label = spResp.Status.Description
+	}
+
+	if len(result.Maintenances) > 0 && result.Level == StatusOperational {
+		result.Level = StatusPlannedMaintenance
+		result.Label = "Scheduled Maintenance"
+	}
+
+	return result, nil
+}
+
+var errNotStatuspage = &ContentTypeError{Want: "a Statuspage.io summary", Got: "other JSON"}
+
+// statuspageComponents flattens the component list into leaf components,
+// resolving each one's group name. Group entries themselves are dropped since
+// their status is just an aggregate of their children.
+func statuspageComponents(raw []statuspageComponent) []Component {
+	groups := make(map[string]string)
+	for _, c := range raw {
+		if c.Group {
+			groups[c.ID] = c.Name
+		}
+	}
+
+	var components []Component
+	for _, c := range raw {
+		if c.Group {
+			continue
+		}
+		components = append(components, Component{
+			ID:     c.ID,
+			Name:   c.Name,
+			Status: c.Status,
+			Group:  groups[c.GroupID],
+			Level:  statuspageComponentLevel(c.Status),
+		})
+	}
+	return components
+}
+
+func componentNames(components []statuspageComponent) []string {
+	var names []string
+	for _, c := range components {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func statuspageComponentLevel(status string) StatusLevel {
+	switch status {
+	case "operational":
+		return StatusOperational
+	case "under_maintenance":
+		return StatusPlannedMaintenance
+	case "degraded_performance", "partial_outage":
+		return StatusDegraded
+	case "major_outage":
+		return StatusMajorDisruption
+	default:
+		return StatusUnknown
+	}
+}
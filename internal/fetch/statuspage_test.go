@@ -0,0 +1,124 @@
+package fetch
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// loadFixture reads a recorded response from testdata.
+func loadFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func componentLevels(components []Component) map[string]StatusLevel {
+	levels := make(map[string]StatusLevel)
+	for _, c := range components {
+		levels[c.Name] = c.Level
+	}
+	return levels
+}
+
+func TestParseStatuspage(t *testing.T) {
+	tests := []struct {
+		fixture      string
+		level        StatusLevel
+		label        string
+		components   map[string]StatusLevel
+		incidents    int
+		maintenances int
+	}{
+		{
+			fixture: "statuspage_operational.json",
+			level:   StatusOperational,
+			label:   "All Systems Operational",
+			components: map[string]StatusLevel{
+				"Git Operations": StatusOperational,
+				"Actions":        StatusOperational,
+			},
+		},
+		{
+			fixture: "statuspage_incident.json",
+			level:   StatusMajorDisruption,
+			label:   "Partial System Outage",
+			components: map[string]StatusLevel{
+				"Jira Software":           StatusDegraded,
+				"Jira Service Management": StatusOperational,
+				"Confluence":              StatusDegraded,
+			},
+			incidents: 1,
+		},
+		{
+			fixture: "statuspage_maintenance.json",
+			level:   StatusPlannedMaintenance,
+			label:   "Scheduled Maintenance",
+			components: map[string]StatusLevel{
+				"Cloudflare Sites and Services": StatusOperational,
+			},
+			maintenances: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			result, err := parseStatuspage(loadFixture(t, tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Level != tt.level || result.Label != tt.label {
+				t.Errorf("got %v %q, want %v %q", result.Level, result.Label, tt.level, tt.label)
+			}
+			levels := componentLevels(result.Components)
+			if len(levels) != len(tt.components) {
+				t.Errorf("got %d components, want %d", len(levels), len(tt.components))
+			}
+			for name, level := range tt.components {
+				if levels[name] != level {
+					t.Errorf("component %q: got %v, want %v", name, levels[name], level)
+				}
+			}
+			if len(result.Incidents) != tt.incidents {
+				t.Errorf("got %d incidents, want %d", len(result.Incidents), tt.incidents)
+			}
+			if len(result.Maintenances) != tt.maintenances {
+				t.Errorf("got %d maintenances, want %d", len(result.Maintenances), tt.maintenances)
+			}
+		})
+	}
+}
+
+func TestParseStatuspageIncident(t *testing.T) {
+	result, err := parseStatuspage(loadFixture(t, "statuspage_incident.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	inc := result.Incidents[0]
+	if inc.Impact != "major" || inc.Status != "identified" || inc.ResolvedAt != nil {
+		t.Errorf("got impact %q status %q resolved %v", inc.Impact, inc.Status, inc.ResolvedAt)
+	}
+	if len(inc.Components) != 1 || inc.Components[0] != "Jira Software" {
+		t.Errorf("got components %v, want [Jira Software]", inc.Components)
+	}
+	// Updates are newest first
+	if !inc.StartedAt.Before(inc.UpdatedAt) {
+		t.Errorf("started %v is not before updated %v", inc.StartedAt, inc.UpdatedAt)
+	}
+	if maint := result.Maintenances; len(maint) != 0 {
+		t.Errorf("got %d maintenances, want 0", len(maint))
+	}
+}
+
+func TestParseStatuspageRejectsOtherJSON(t *testing.T) {
+	for _, body := range []string{`{}`, `{"data": [{"id": 1}]}`, `{"page": {"name": "Not a summary"}}`} {
+		_, err := parseStatuspage([]byte(body))
+		if !errors.Is(err, errNotStatuspage) {
+			t.Errorf("%s: got %v, want errNotStatuspage", body, err)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const upptimeSummaryPath = "history/summary.json"
+
+type upptimeSite struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Slug   string `json:"slug"`
+	Status string `json:"status"`
+	Uptime string `json:"uptime"`
+}
+
+// upptimeProvider reads the history/summary.json that Upptime commits to its
+// GitHub repository. Besides a raw URL to that file, it accepts the
+// repository itself or the owner.github.io/repo status site.
+type upptimeProvider struct{}
+
+func (upptimeProvider) Name() string { return "upptime" }
+
+func (upptimeProvider) Detect(u *url.URL) Detection {
+	switch {
+	case strings.HasSuffix(u.Path, "/"+upptimeSummaryPath):
+		return DetectYes
+	case upptimeSummaryURL(u) != "":
+		return DetectProbe
+	default:
+		return DetectNo
+	}
+}
+
+func (p upptimeProvider) Fetch(ctx context.Context, c *Client, u *url.URL) (*Result, error) {
+	urlStr := u.String()
+	if !strings.HasSuffix(u.Path, "/"+upptimeSummaryPath) {
+		urlStr = upptimeSummaryURL(u)
+		if urlStr == "" {
+			return nil, fmt.Errorf("can't find the Upptime repository for %s", u)
+		}
+	}
+
+	result, err := c.getResult(ctx, p.Name(), urlStr, "application/json", expect(contentJSON, parseUpptime))
+	if err != nil {
+		return nil, err
+	}
+	result.SourceURL = urlStr
+	return result, nil
+}
+
+// upptimeSummaryURL returns the raw summary.json for a github.com repository
+// or a github.io project site, or "" for any other URL.
+func upptimeSummaryURL(u *url.URL) string {
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	var owner, repo string
+	switch host := u.Hostname(); {
+	case host == "github.com" && len(parts) >= 2:
+		owner, repo = parts[0], parts[1]
+	case strings.HasSuffix(host, ".github.io") && parts[0] != "":
+		owner, repo = strings.TrimSuffix(host, ".github.io"), parts[0]
+	default:
+		return ""
+	}
+	return fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/HEAD/%s", owner, repo, upptimeSummaryPath)
+}
+
+func parseUpptime(body []byte) (*Result, error) {
+	var sites []upptimeSite
+	if err := json.Unmarshal(body, &sites); err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		CheckedAt: time.Now(),
+		Level:     StatusOperational,
+		Label:     "All Systems Operational",
+		ParseNote: "Parsed Upptime summary",
+	}
+
+	down, degraded := 0, 0
+	for _, site := range sites {
+		if site.Slug == "" || site.Status == "" {
+			return nil, &ContentTypeError{Want: "an Upptime summary", Got: "other JSON"}
+		}
+		level := StatusUnknown
+		switch site.Status {
+		case "up":
+			level = StatusOperational
+		case "degraded":
+			level = StatusDegraded
+			degraded++
+		case "down":
+			level = StatusMajorDisruption
+			down++
+		}
+		status := site.Status
+		if site.Uptime != "" {
+			status += ", " + site.Uptime + " uptime"
+		}
+		result.Components = append(result.Components, Component{
+			ID:     site.Slug,
+			Name:   site.Name,
+			Status: status,
+			Level:  level,
+		})
+	}
+
+	switch {
+	case down == 1:
+		result.Level = StatusMajorDisruption
+		result.Label = "1 Site Down"
+	case down > 1:
+		result.Level = StatusMajorDisruption
+		result.Label = fmt.Sprintf("%d Sites Down", down)
+	case degraded > 0:
+		result.Level = StatusDegraded
+		result.Label = "Degraded Performance"
+	}
+
+	return result, nil
+}
@@ -0,0 +1,226 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	uptimekumaPagePath = "/status/"
+	uptimekumaAPIPath  = "/api/status-page/"
+)
+
+type uptimekumaPage struct {
+	Config *struct {
+		Title string `json:"title"`
+	} `json:"config"`
+	// Incident is the message pinned to the top of the page, if any
+	Incident *struct {
+		ID              int    `json:"id"`
+		Title           string `json:"title"`
+		Content         string `json:"content"`
+		Style           string `json:"style"`
+		CreatedDate     string `json:"createdDate"`
+		LastUpdatedDate string `json:"lastUpdatedDate"`
+	} `json:"incident"`
+	PublicGroupList []struct {
+		Name        string `json:"name"`
+		MonitorList []struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"monitorList"`
+	} `json:"publicGroupList"`
+	MaintenanceList []struct {
+		ID          int    `json:"id"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Status      string `json:"status"`
+	} `json:"maintenanceList"`
+}
+
+type uptimekumaHeartbeats struct {
+	// HeartbeatList maps monitor IDs to their recent heartbeats
+	HeartbeatList map[string][]struct {
+		Status int    `json:"status"`
+		Time   string `json:"time"`
+		Msg    string `json:"msg"`
+	} `json:"heartbeatList"`
+}
+
+// Uptime Kuma heartbeat statuses.
+const (
+	uptimekumaDown        = 0
+	uptimekumaUp          = 1
+	uptimekumaPending     = 2
+	uptimekumaMaintenance = 3
+)
+
+// uptimekumaProvider reads an Uptime Kuma status page: its monitor list and
+// pinned incident, then the latest heartbeat of each monitor.
+type uptimekumaProvider struct{}
+
+func (uptimekumaProvider) Name() string { return "uptimekuma" }
+
+func (uptimekumaProvider) Detect(u *url.URL) Detection {
+	switch {
+	case strings.HasPrefix(u.Path, uptimekumaAPIPath):
+		return DetectYes
+	case uptimekumaSlug(u) != "":
+		return DetectProbe
+	default:
+		return DetectNo
+	}
+}
+
+func (uptimekumaProvider) Fetch(ctx context.Context, c *Client, u *url.URL) (*Result, error) {
+	slug := uptimekumaSlug(u)
+	if slug == "" {
+		return nil, &ContentTypeError{Want: "an Uptime Kuma status page", Got: "no page slug in URL"}
+	}
+	base := url.URL{Scheme: u.Scheme, Host: u.Host}
+	pageURL := base.String() + uptimekumaAPIPath + url.PathEscape(slug)
+
+	var page uptimekumaPage
+	if err := c.getJSON(ctx, pageURL, &page); err != nil {
+		return nil, err
+	}
+	if page.Config == nil {
+		return nil, &ContentTypeError{Want: "an Uptime Kuma status page", Got: "other JSON"}
+	}
+
+	var heartbeats uptimekumaHeartbeats
+	if err := c.getJSON(ctx, base.String()+uptimekumaAPIPath+"heartbeat/"+url.PathEscape(slug), &heartbeats); err != nil {
+		return nil, err
+	}
+
+	result := parseUptimekuma(page, heartbeats)
+	result.SourceURL = pageURL
+	return result, nil
+}
+
+// uptimekumaSlug finds the page slug in a /status/<slug> page URL or an API
+// URL.
+func uptimekumaSlug(u *url.URL) string {
+	var rest string
+	switch {
+	case strings.HasPrefix(u.Path, uptimekumaAPIPath):
+		rest = strings.TrimPrefix(u.Path, uptimekumaAPIPath)
+		rest = strings.TrimPrefix(rest, "heartbeat/")
+	case strings.HasPrefix(u.Path, uptimekumaPagePath):
+		rest = strings.TrimPrefix(u.Path, uptimekumaPagePath)
+	default:
+		return ""
+	}
+	rest = strings.TrimSuffix(rest, "/")
+	if rest == "" || strings.Contains(rest, "/") {
+		return ""
+	}
+	return rest
+}
+
+func parseUptimekuma(page uptimekumaPage, heartbeats uptimekumaHeartbeats) *Result {
+	result := &Result{
+		CheckedAt: time.Now(),
+		Level:     StatusOperational,
+		Label:     "All Systems Operational",
+		ParseNote: "Parsed Uptime Kuma status page",
+	}
+
+	down := 0
+	for _, group := range page.PublicGroupList {
+		for _, monitor := range group.MonitorList {
+			id := strconv.Itoa(monitor.ID)
+			status, level := "unknown", StatusUnknown
+			// Heartbeats are oldest first
+			if beats := heartbeats.HeartbeatList[id]; len(beats) > 0 {
+				status, level = uptimekumaStatus(beats[len(beats)-1].Status)
+			}
+			result.Components = append(result.Components, Component{
+				ID:     id,
+				Name:   monitor.Name,
+				Status: status,
+				Group:  group.Name,
+				Level:  level,
+			})
+			if level == StatusMajorDisruption {
+				down++
+			}
+			if level > result.Level {
+				result.Level = level
+			}
+		}
+	}
+	switch result.Level {
+	case StatusMajorDisruption:
+		result.Label = "1 Monitor Down"
+		if down > 1 {
+			result.Label = fmt.Sprintf("%d Monitors Down", down)
+		}
+	case StatusDegraded:
+		result.Label = "Monitors Pending"
+	case StatusPlannedMaintenance:
+		result.Label = "Under Maintenance"
+	}
+
+	if inc := page.Incident; inc != nil {
+		incident := Incident{
+			ID:        strconv.Itoa(inc.ID),
+			Title:     inc.Title,
+			Status:    "pinned",
+			Impact:    levelImpact(uptimekumaStyleLevel(inc.Style)),
+			StartedAt: parseTimestamp(inc.CreatedDate),
+			UpdatedAt: parseTimestamp(firstNonEmpty(inc.LastUpdatedDate, inc.CreatedDate)),
+		}
+		if inc.Content != "" {
+			incident.Updates = []IncidentUpdate{{Body: inc.Content, Status: incident.Status, CreatedAt: incident.UpdatedAt}}
+		}
+		result.Incidents = append(result.Incidents, incident)
+	}
+
+	for _, maint := range page.MaintenanceList {
+		status := "scheduled"
+		if maint.Status == "under-maintenance" {
+			status = "in_progress"
+		}
+		result.Maintenances = append(result.Maintenances, Maintenance{
+			ID:     strconv.Itoa(maint.ID),
+			Title:  maint.Title,
+			Status: status,
+			Impact: impactMaintenance,
+		})
+	}
+
+	return result
+}
+
+// uptimekumaStyleLevel reads how bad a pinned incident is from the Bootstrap
+// style it is shown in. Other styles, such as info and primary, are notices.
+func uptimekumaStyleLevel(style string) StatusLevel {
+	switch style {
+	case "danger":
+		return StatusMajorDisruption
+	case "warning":
+		return StatusDegraded
+	default:
+		return StatusOperational
+	}
+}
+
+func uptimekumaStatus(status int) (string, StatusLevel) {
+	switch status {
+	case uptimekumaUp:
+		return "up", StatusOperational
+	case uptimekumaDown:
+		return "down", StatusMajorDisruption
+	case uptimekumaPending:
+		return "pending", StatusDegraded
+	case uptimekumaMaintenance:
+		return "maintenance", StatusPlannedMaintenance
+	default:
+		return "unknown", StatusUnknown
+	}
+}
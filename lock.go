@@ -0,0 +1,22 @@
+package main
+
+import "fmt"
+
+// storeLockFile is held by the process that owns ~/.lazystatus, the TUI or
+// serve, for as long as it runs. Both write config.json and compact the
+// history, so two of them would undo each other's changes. One-shot
+// commands like check only read the store and don't take it.
+const storeLockFile = "lock"
+
+// StoreLockedError reports that another lazystatus process owns the store.
+type StoreLockedError struct {
+	PID int // 0 if unknown
+}
+
+func (e *StoreLockedError) Error() string {
+	owner := "another lazystatus"
+	if e.PID > 0 {
+		owner = fmt.Sprintf("another lazystatus (pid %d)", e.PID)
+	}
+	return owner + " is already running; the TUI and serve can't share ~/.lazystatus, so stop it first"
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// lockStore is a no-op on platforms without flock yet.
+func lockStore(dir string) (*os.File, error) {
+	return nil, nil
+}
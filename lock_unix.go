@@ -0,0 +1,38 @@
+//go:build unix
+
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// lockStore takes an exclusive lock on dir's lock file, returning a
+// *StoreLockedError if another process holds it. The lock is released when
+// the returned file is closed or the process exits.
+func lockStore(dir string) (*os.File, error) {
+	f, err := os.OpenFile(filepath.Join(dir, storeLockFile), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		defer f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			data, _ := io.ReadAll(f)
+			pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+			return nil, &StoreLockedError{PID: pid}
+		}
+		return nil, err
+	}
+
+	// The PID is only for the error message above
+	if err := f.Truncate(0); err == nil {
+		f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	return f, nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -18,6 +19,10 @@ func main() {
 		case "--help", "-h":
 			printHelp()
 			return
+		case "check":
+			os.Exit(runCheck(os.Args[2:], os.Stdout, os.Stderr))
+		case "serve":
+			os.Exit(runServe(os.Args[2:], os.Stdout, os.Stderr))
 		}
 	}
 
@@ -27,17 +32,42 @@ func main() {
 		os.Exit(1)
 	}
 
+	webhooks, err := NewWebhookNotifier(sm.Notifications().Webhooks)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error in notifications config: %v\n", err)
+		os.Exit(1)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	events, unsubscribe := sm.Events().Subscribe(64)
+	go webhooks.Run(ctx, events)
+
 	p := tea.NewProgram(
 		initialModel(sm),
 		tea.WithAltScreen(),
 	)
 
+	hooks := NewHookRunner(sm)
+	hooks.OnResult = func(result HookResult) {
+		p.Send(hookResultMsg(result))
+	}
+	hookEvents, unsubscribeHooks := sm.Events().Subscribe(64)
+	hooksDone := make(chan struct{})
+	go func() {
+		hooks.Run(ctx, hookEvents)
+		close(hooksDone)
+	}()
+
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
+	cancel()
+	<-hooksDone
+	unsubscribe()
+	unsubscribeHooks()
 	sm.Save()
+	sm.Close()
 }
 
 func printHelp() {
@@ -45,6 +75,11 @@ func printHelp() {
 	fmt.Println("")
 	fmt.Println("Usage:")
 	fmt.Println("  lazystatus                 Start the TUI")
+	fmt.Println("  lazystatus check [name...] Check services once; exit 0/1/2/3 (OK/WARNING/CRITICAL/UNKNOWN)")
+	fmt.Println("    -o, --output FORMAT      Output as text (default), json or ndjson")
+	fmt.Println("  lazystatus serve           Refresh services in the background (no TUI)")
+	fmt.Println("    --metrics ADDR           Serve Prometheus metrics at ADDR/metrics")
+	fmt.Println("    --api ADDR               Serve the JSON API at ADDR/api/services")
 	fmt.Println("  lazystatus --version       Show version")
 	fmt.Println("  lazystatus --help          Show this help")
 	fmt.Println("")
@@ -60,8 +95,11 @@ func printHelp() {
 	fmt.Println("  e          Edit service")
 	fmt.Println("  d          Delete service")
 	fmt.Println("  o          Open service URL in browser")
+	fmt.Println("  c          Expand/collapse component groups")
+	fmt.Println("  l          Show/hide the on_change event log")
 	fmt.Println("  Enter      Refresh selected service")
 	fmt.Println("  r          Refresh all services")
+	fmt.Println("  D          Re-detect the selected service's provider")
 	fmt.Println("")
 	fmt.Println("Other:")
 	fmt.Println("  ?          Show/hide help")
@@ -75,6 +113,7 @@ func printHelp() {
 	fmt.Println("")
 	fmt.Println("Configuration:")
 	fmt.Println("  Config file: ~/.lazystatus/config.json")
+	fmt.Println("  History:     ~/.lazystatus/history.jsonl")
 	fmt.Println("")
 	fmt.Println("🎭 Powered by Charm - https://charm.sh")
 }
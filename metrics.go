@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics exposes service state in the Prometheus text exposition format.
+// Gauges are read from the manager at scrape time; fetch durations and error
+// counters are accumulated from scheduler observations.
+type Metrics struct {
+	manager *ServiceManager
+
+	mu        sync.Mutex
+	durations map[string]time.Duration
+	errors    map[string]map[string]uint64 // service ID -> error type -> count
+}
+
+func NewMetrics(sm *ServiceManager) *Metrics {
+	return &Metrics{
+		manager:   sm,
+		durations: make(map[string]time.Duration),
+		errors:    make(map[string]map[string]uint64),
+	}
+}
+
+// Observe records a completed fetch. It matches Scheduler.OnFetch.
+func (m *Metrics) Observe(obs FetchObservation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.durations[obs.ID] = obs.Duration
+	if errType := fetchErrorType(obs); errType != "" {
+		if m.errors[obs.ID] == nil {
+			m.errors[obs.ID] = make(map[string]uint64)
+		}
+		m.errors[obs.ID][errType]++
+	}
+}
+
+// fetchErrorType classifies a failed fetch, or returns "" if it succeeded.
+func fetchErrorType(obs FetchObservation) string {
+	switch {
+	case obs.TimedOut:
+		return "timeout"
+	case obs.Err != nil:
+		return "request"
+	case obs.Result == nil:
+		return ""
+	}
+	switch convertStatusLevel(obs.Result.Level) {
+	case StatusConnectionError:
+		return "connection"
+	case StatusParseError:
+		return "parse"
+	default:
+		return ""
+	}
+}
+
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// WriteTo writes every metric family in the text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	services := m.manager.List()
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	writeFamily(&b, "lazystatus_service_status_level", "gauge",
+		"Current status level: 0=unknown 1=operational 2=planned_maintenance 3=degraded 4=major_disruption 5=connection_error 6=parse_error.")
+	for _, svc := range services {
+		fmt.Fprintf(&b, "lazystatus_service_status_level{%s} %d\n", serviceLabels(svc), svc.StatusLevel)
+	}
+
+	writeFamily(&b, "lazystatus_service_flapping", "gauge", "1 if the service is flapping between levels.")
+	for _, svc := range services {
+		flapping := 0
+		if svc.Flapping {
+			flapping = 1
+		}
+		fmt.Fprintf(&b, "lazystatus_service_flapping{%s} %d\n", serviceLabels(svc), flapping)
+	}
+
+	writeFamily(&b, "lazystatus_service_open_incidents", "gauge", "Number of unresolved incidents.")
+	for _, svc := range services {
+		fmt.Fprintf(&b, "lazystatus_service_open_incidents{%s} %d\n", serviceLabels(svc), svc.OpenIncidents())
+	}
+
+	writeFamily(&b, "lazystatus_service_active_maintenances", "gauge", "Number of maintenance windows in progress.")
+	for _, svc := range services {
+		fmt.Fprintf(&b, "lazystatus_service_active_maintenances{%s} %d\n", serviceLabels(svc), svc.ActiveMaintenances(now))
+	}
+
+	writeFamily(&b, "lazystatus_service_last_check_timestamp_seconds", "gauge", "Unix time of the last completed check.")
+	for _, svc := range services {
+		if svc.Config.LastChecked.IsZero() {
+			continue
+		}
+		fmt.Fprintf(&b, "lazystatus_service_last_check_timestamp_seconds{%s} %d\n", serviceLabels(svc), svc.Config.LastChecked.Unix())
+	}
+
+	writeFamily(&b, "lazystatus_service_fetch_duration_seconds", "gauge", "Duration of the last fetch.")
+	for _, svc := range services {
+		if d, ok := m.durations[svc.Config.ID]; ok {
+			fmt.Fprintf(&b, "lazystatus_service_fetch_duration_seconds{%s} %g\n", serviceLabels(svc), d.Seconds())
+		}
+	}
+
+	writeFamily(&b, "lazystatus_service_fetch_errors_total", "counter", "Failed fetches by error type (timeout, request, connection, parse).")
+	for _, svc := range services {
+		counts := m.errors[svc.Config.ID]
+		types := make([]string, 0, len(counts))
+		for errType := range counts {
+			types = append(types, errType)
+		}
+		sort.Strings(types)
+		for _, errType := range types {
+			fmt.Fprintf(&b, "lazystatus_service_fetch_errors_total{%s,type=%q} %d\n", serviceLabels(svc), errType, counts[errType])
+		}
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func writeFamily(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func serviceLabels(svc ServiceState) string {
+	return fmt.Sprintf(`id="%s",name="%s"`, labelEscaper.Replace(svc.Config.ID), labelEscaper.Replace(svc.Config.Name))
+}
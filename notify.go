@@ -0,0 +1,166 @@
+package main
+
+import "fmt"
+
+// Notification modes for ServiceConfig.Notify.
+const (
+	// NotifyTransitions notifies whenever the status level changes.
+	NotifyTransitions = "transitions"
+	// NotifyProblems only notifies when a service becomes degraded or
+	// disrupted, and when it recovers.
+	NotifyProblems = "problems"
+	NotifyOff      = "off"
+)
+
+type Urgency int
+
+const (
+	UrgencyLow Urgency = iota
+	UrgencyNormal
+	UrgencyCritical
+)
+
+// Notification is a desktop notification. Clicking it opens URL.
+type Notification struct {
+	Title   string
+	Body    string
+	URL     string
+	Urgency Urgency
+}
+
+// shouldNotify reports whether a change from old to new is worth a
+// notification under mode.
+func shouldNotify(mode string, old, new StatusLevel) bool {
+	if old == new {
+		return false
+	}
+
+	switch mode {
+	case "", NotifyTransitions:
+		return true
+	case NotifyProblems:
+		return isProblem(old) || isProblem(new)
+	default:
+		return false
+	}
+}
+
+// notificationFor decides what to notify, if anything, after a refresh took
+// the service from prev to svc. A flapping service only notifies when it
+// starts and stops flapping. Failed checks only notify if monitoring is set,
+// and recovering from one only notifies if the page changed meanwhile. As for
+// events, the first check after startup is never a transition.
+func notificationFor(prev, svc ServiceState, monitoring bool) (Notification, bool) {
+	switch svc.Config.Notify {
+	case "", NotifyTransitions, NotifyProblems:
+	default:
+		return Notification{}, false
+	}
+	if firstCheck(prev) {
+		return Notification{}, false
+	}
+
+	switch {
+	case svc.Flapping && !prev.Flapping:
+		return Notification{
+			Title:   svc.Config.Name,
+			Body:    "Flapping between levels; notifications paused",
+			URL:     svc.Config.URL,
+			Urgency: UrgencyNormal,
+		}, true
+	case svc.Flapping:
+		return Notification{}, false
+	case prev.Flapping:
+		return Notification{
+			Title:   svc.Config.Name,
+			Body:    "Stopped flapping: " + svc.StatusLevel.String(),
+			URL:     svc.Config.URL,
+			Urgency: urgencyFor(svc.StatusLevel),
+		}, true
+	}
+
+	old := prev.StatusLevel
+	switch {
+	case svc.StatusLevel.IsMonitoringProblem():
+		if !monitoring || old.IsMonitoringProblem() {
+			return Notification{}, false
+		}
+		return Notification{
+			Title:   svc.Config.Name,
+			Body:    "Can't check the status page: " + svc.StatusLevel.String(),
+			URL:     svc.Config.URL,
+			Urgency: UrgencyLow,
+		}, true
+	case old.IsMonitoringProblem():
+		old = prev.pageLevel
+	}
+
+	if !shouldNotify(svc.Config.Notify, old, svc.StatusLevel) {
+		return Notification{}, false
+	}
+	return transitionNotification(svc, old), true
+}
+
+// notifiesOffline reports whether losing every page is worth a notification:
+// failed checks aren't routed off, and at least one service would have
+// notified about its own.
+func notifiesOffline(monitoring bool, services []ServiceState) bool {
+	if !monitoring {
+		return false
+	}
+	for _, svc := range services {
+		switch svc.Config.Notify {
+		case "", NotifyTransitions, NotifyProblems:
+			return true
+		}
+	}
+	return false
+}
+
+// offlineNotification is sent instead of one notification per service when
+// every check fails at once.
+func offlineNotification() Notification {
+	return Notification{
+		Title:   "lazystatus",
+		Body:    "Can't reach any status pages. Check your network or VPN.",
+		Urgency: UrgencyNormal,
+	}
+}
+
+// onlineNotification is sent when a page can be read again after being
+// offline.
+func onlineNotification() Notification {
+	return Notification{
+		Title:   "lazystatus",
+		Body:    "Status pages are reachable again.",
+		Urgency: UrgencyLow,
+	}
+}
+
+func isProblem(level StatusLevel) bool {
+	return level == StatusDegraded || level == StatusMajorDisruption
+}
+
+func urgencyFor(level StatusLevel) Urgency {
+	switch level {
+	case StatusMajorDisruption:
+		return UrgencyCritical
+	case StatusDegraded:
+		return UrgencyNormal
+	default:
+		return UrgencyLow
+	}
+}
+
+func transitionNotification(svc ServiceState, old StatusLevel) Notification {
+	body := fmt.Sprintf("%s → %s", old, svc.StatusLevel)
+	if svc.Label != "" && svc.Label != svc.StatusLevel.String() {
+		body += "\n" + svc.Label
+	}
+	return Notification{
+		Title:   svc.Config.Name,
+		Body:    body,
+		URL:     svc.Config.URL,
+		Urgency: urgencyFor(svc.StatusLevel),
+	}
+}
@@ -0,0 +1,62 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+var urgencyNames = map[Urgency]string{
+	UrgencyLow:      "low",
+	UrgencyNormal:   "normal",
+	UrgencyCritical: "critical",
+}
+
+// clickTimeout bounds how long notify-send waits for a notification to be
+// clicked. Critical notifications never expire, so without it every one
+// would leave a process waiting until it is dismissed.
+const clickTimeout = 10 * time.Minute
+
+// sendNotification shows n with notify-send, which talks to the freedesktop
+// notification service over D-Bus. If n has a URL it blocks until the
+// notification is closed or clickTimeout passes, so that clicking it can
+// open the status page.
+func sendNotification(n Notification) error {
+	path, err := exec.LookPath("notify-send")
+	if err != nil {
+		return fmt.Errorf("notify-send not found: %w", err)
+	}
+
+	args := []string{"--app-name=lazystatus", "--urgency=" + urgencyNames[n.Urgency]}
+	if n.URL == "" {
+		// Nothing to open, so no reason to wait for a click
+		return exec.Command(path, append(args, n.Title, n.Body)...).Run()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), clickTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, append(args, "--action=default=Open status page", "--wait", n.Title, n.Body)...).Output()
+	if ctx.Err() != nil {
+		// Not clicked in time; the notification itself stays up
+		return nil
+	}
+	if err != nil {
+		// libnotify before 0.7.10 has no --action or --wait; show the
+		// notification without a click action instead
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && strings.Contains(string(exitErr.Stderr), "Unknown option") {
+			return exec.Command(path, append(args, n.Title, n.Body)...).Run()
+		}
+		return err
+	}
+
+	if strings.TrimSpace(string(out)) == "default" && n.URL != "" {
+		return exec.Command("xdg-open", n.URL).Run()
+	}
+	return nil
+}
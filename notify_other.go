@@ -0,0 +1,8 @@
+//go:build !linux
+
+package main
+
+// sendNotification is a no-op on platforms without a notifier yet.
+func sendNotification(n Notification) error {
+	return nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// reportVersion is bumped whenever a field in the JSON reports is removed or
+// changes meaning. Adding fields does not bump it.
+const reportVersion = 1
+
+// Output formats for headless commands.
+const (
+	outputText   = "text"
+	outputJSON   = "json"
+	outputNDJSON = "ndjson"
+)
+
+func validOutput(format string) error {
+	switch format {
+	case outputText, outputJSON, outputNDJSON:
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q (want text, json or ndjson)", format)
+	}
+}
+
+type componentReport struct {
+	Name   string `json:"name"`
+	Group  string `json:"group,omitempty"`
+	Status string `json:"status"`
+	Level  string `json:"level"`
+}
+
+type serviceReport struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	URL          string            `json:"url"`
+	Level        string            `json:"level"`
+	Status       string            `json:"status"`
+	Label        string            `json:"label,omitempty"`
+	State        string            `json:"state"`
+	CheckedAt    *time.Time        `json:"checked_at,omitempty"`
+	Incidents    []Incident        `json:"incidents"`
+	Maintenances []Maintenance     `json:"maintenances"`
+	Components   []componentReport `json:"components,omitempty"`
+	ParseNote    string            `json:"parse_note,omitempty"`
+	LastError    string            `json:"last_error,omitempty"`
+	Flapping     bool              `json:"flapping,omitempty"`
+}
+
+// checkReport is the single document written by --output json.
+type checkReport struct {
+	Version     int             `json:"version"`
+	GeneratedAt time.Time       `json:"generated_at"`
+	State       string          `json:"state"`
+	ExitCode    int             `json:"exit_code"`
+	Services    []serviceReport `json:"services"`
+}
+
+// streamRecord is one line of --output ndjson. Each service is written as it
+// finishes with type "service"; a final "summary" line carries the overall
+// state.
+type streamRecord struct {
+	Version  int            `json:"version"`
+	Type     string         `json:"type"`
+	Service  *serviceReport `json:"service,omitempty"`
+	State    string         `json:"state,omitempty"`
+	ExitCode *int           `json:"exit_code,omitempty"`
+}
+
+func newServiceReport(svc ServiceState) serviceReport {
+	report := serviceReport{
+		ID:           svc.Config.ID,
+		Name:         svc.Config.Name,
+		URL:          svc.Config.URL,
+		Level:        svc.StatusLevel.Key(),
+		Status:       svc.StatusLevel.String(),
+		Label:        svc.Label,
+		State:        exitLabels[exitCode(svc.StatusLevel)],
+		Incidents:    svc.Incidents,
+		Maintenances: svc.Maintenances,
+		ParseNote:    svc.ParseNote,
+		LastError:    svc.LastError,
+		Flapping:     svc.Flapping,
+	}
+	if !svc.Config.LastChecked.IsZero() {
+		checkedAt := svc.Config.LastChecked
+		report.CheckedAt = &checkedAt
+	}
+	// Always emit arrays so consumers don't have to handle null
+	if report.Incidents == nil {
+		report.Incidents = []Incident{}
+	}
+	if report.Maintenances == nil {
+		report.Maintenances = []Maintenance{}
+	}
+	for _, comp := range svc.Components {
+		report.Components = append(report.Components, componentReport{
+			Name:   comp.Name,
+			Group:  comp.Group,
+			Status: comp.Status,
+			Level:  comp.Level.Key(),
+		})
+	}
+	return report
+}
+
+func writeJSONReport(w io.Writer, services []ServiceState, overall int) error {
+	report := checkReport{
+		Version:     reportVersion,
+		GeneratedAt: time.Now().UTC(),
+		State:       exitLabels[overall],
+		ExitCode:    overall,
+		Services:    make([]serviceReport, 0, len(services)),
+	}
+	for _, svc := range services {
+		report.Services = append(report.Services, newServiceReport(svc))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+// ndjsonWriter writes stream records one per line. It is safe for concurrent
+// use so services can be written as their fetches complete.
+type ndjsonWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	return &ndjsonWriter{enc: json.NewEncoder(w)}
+}
+
+func (n *ndjsonWriter) WriteService(svc ServiceState) error {
+	report := newServiceReport(svc)
+	return n.write(streamRecord{Version: reportVersion, Type: "service", Service: &report})
+}
+
+func (n *ndjsonWriter) WriteSummary(overall int) error {
+	return n.write(streamRecord{Version: reportVersion, Type: "summary", State: exitLabels[overall], ExitCode: &overall})
+}
+
+func (n *ndjsonWriter) write(record streamRecord) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.enc.Encode(record)
+}
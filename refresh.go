@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jakeasaurus/lazystatus/internal/fetch"
+)
+
+// newFetchClient returns a client that retries, backs off and limits
+// response sizes as configured in settings.
+func newFetchClient(settings Settings) *fetch.Client {
+	retry := fetch.DefaultRetryPolicy
+	retry.MaxAttempts = settings.RetryAttempts
+	cooldown := time.Duration(settings.CircuitBreakerCooldown) * time.Second
+	client := fetch.NewClientWithPolicy(retry, settings.CircuitBreakerThreshold, cooldown)
+	client.MaxBodySize = int64(settings.MaxBodyKB) * 1024
+	return client
+}
+
+// redetectAfter is how many checks in a row can fail through the detected
+// provider before detection runs again.
+const redetectAfter = 3
+
+func fetchTarget(cfg ServiceConfig) fetch.Target {
+	target := fetch.Target{
+		URL:      cfg.URL,
+		Provider: cfg.Provider,
+	}
+	if cfg.Provider == "" && cfg.Detected != nil && fetch.Lookup(cfg.Detected.Provider) != nil {
+		target.URL = cfg.Detected.Endpoint
+		target.Provider = cfg.Detected.Provider
+	}
+	if cfg.Components != nil {
+		target.Components = fetch.ComponentFilter{
+			Include: cfg.Components.Include,
+			Exclude: cfg.Components.Exclude,
+		}
+	}
+	target.Scope = fetch.Scope{Products: cfg.Products, Regions: cfg.Regions}
+	return target
+}
+
+// ApplyResult records the outcome of fetching a service.
+func (sm *ServiceManager) ApplyResult(id string, result *fetch.Result, err error) error {
+	if err != nil {
+		return sm.UpdateStatus(id, StatusConnectionError, "", nil, nil, nil, "", err.Error())
+	}
+	sm.recordDetection(id, result)
+
+	incidents := convertIncidents(result.Incidents)
+	maintenances := convertMaintenances(result.Maintenances)
+	components := convertComponents(result.Components)
+	level := convertStatusLevel(result.Level)
+	return sm.UpdateStatus(id, level, result.Label, incidents, maintenances, components, result.ParseNote, "")
+}
+
+// recordDetection remembers the provider and endpoint of a successful check
+// and forgets them after redetectAfter failed checks in a row, in case the
+// vendor has moved to a different status page. Fallback providers aren't
+// remembered: they read almost anything, so a page that only reached them
+// because a probe failed once would never be detected properly again.
+func (sm *ServiceManager) recordDetection(id string, result *fetch.Result) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	index := sm.indexOf(id)
+	if index < 0 {
+		return
+	}
+	state := &sm.states[index]
+	if state.Config.Provider != "" {
+		return
+	}
+
+	switch {
+	case result.Level == fetch.StatusConnectionError || result.Level == fetch.StatusParseError:
+		if state.Config.Detected == nil {
+			return
+		}
+		state.detectFailures++
+		if state.detectFailures < redetectAfter {
+			return
+		}
+		state.Config.Detected = nil
+	case result.Fallback:
+		return
+	default:
+		detected := &DetectedSource{Provider: result.Provider, Endpoint: result.SourceURL}
+		if state.Config.Detected != nil && *state.Config.Detected == *detected {
+			state.detectFailures = 0
+			return
+		}
+		state.Config.Detected = detected
+	}
+	state.detectFailures = 0
+	sm.config.Services[index].Detected = state.Config.Detected
+}
+
+// ResetDetection forgets the service's detected provider, so the next check
+// tries every provider again.
+func (sm *ServiceManager) ResetDetection(id string) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	index := sm.indexOf(id)
+	if index < 0 {
+		return fmt.Errorf("service %s not found", id)
+	}
+	sm.states[index].Config.Detected = nil
+	sm.states[index].detectFailures = 0
+	sm.config.Services[index].Detected = nil
+	return nil
+}
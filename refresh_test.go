@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jakeasaurus/lazystatus/internal/fetch"
+)
+
+const testSummary = `{
+  "page": {"id": "kctbh9vrtdwd", "name": "GitHub", "url": "https://www.githubstatus.com"},
+  "status": {"indicator": "none", "description": "All Systems Operational"},
+  "components": [],
+  "incidents": [],
+  "scheduled_maintenances": []
+}`
+
+func newTestManager(t *testing.T) *ServiceManager {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	sm, err := LoadServiceManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sm.Close() })
+	return sm
+}
+
+func TestDetectionSkipsFallback(t *testing.T) {
+	// The Statuspage.io API times out once; the HTML page always works
+	var summaryRequests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/summary.json":
+			if summaryRequests.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(testSummary))
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html><body><h1>All Systems Operational</h1></body></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	sm := newTestManager(t)
+	id, err := sm.Add(ServiceConfig{Name: "GitHub", URL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := fetch.NewClientWithPolicy(fetch.RetryPolicy{MaxAttempts: 1}, 0, 0)
+	check := func() string {
+		svc, _ := sm.Get(id)
+		result, err := client.Fetch(context.Background(), fetchTarget(svc.Config))
+		if err := sm.ApplyResult(id, result, err); err != nil {
+			t.Fatal(err)
+		}
+		return result.Provider
+	}
+
+	if provider := check(); provider != "html" {
+		t.Fatalf("first check: got provider %q, want html", provider)
+	}
+	if svc, _ := sm.Get(id); svc.Config.Detected != nil {
+		t.Fatalf("fallback was remembered: %+v", *svc.Config.Detected)
+	}
+
+	if provider := check(); provider != "statuspage" {
+		t.Fatalf("second check: got provider %q, want statuspage", provider)
+	}
+	want := DetectedSource{Provider: "statuspage", Endpoint: srv.URL + "/api/v2/summary.json"}
+	if svc, _ := sm.Get(id); svc.Config.Detected == nil || *svc.Config.Detected != want {
+		t.Fatalf("got detected %+v, want %+v", svc.Config.Detected, want)
+	}
+}
+
+func TestDetectionForgottenAfterFailures(t *testing.T) {
+	sm := newTestManager(t)
+	id, err := sm.Add(ServiceConfig{Name: "GitHub", URL: "https://www.githubstatus.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok := &fetch.Result{
+		Level:     fetch.StatusOperational,
+		Provider:  "statuspage",
+		SourceURL: "https://www.githubstatus.com/api/v2/summary.json",
+	}
+	failed := &fetch.Result{Level: fetch.StatusConnectionError, Provider: "statuspage"}
+	detected := func() *DetectedSource {
+		svc, _ := sm.Get(id)
+		return svc.Config.Detected
+	}
+
+	sm.ApplyResult(id, ok, nil)
+	if detected() == nil {
+		t.Fatal("successful check wasn't remembered")
+	}
+
+	// A success in between starts the count over
+	for range redetectAfter - 1 {
+		sm.ApplyResult(id, failed, nil)
+	}
+	sm.ApplyResult(id, ok, nil)
+	for range redetectAfter - 1 {
+		sm.ApplyResult(id, failed, nil)
+	}
+	if detected() == nil {
+		t.Fatalf("forgotten after fewer than %d failures in a row", redetectAfter)
+	}
+
+	sm.ApplyResult(id, failed, nil)
+	if d := detected(); d != nil {
+		t.Fatalf("still detected after %d failures in a row: %+v", redetectAfter, *d)
+	}
+}
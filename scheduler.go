@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jakeasaurus/lazystatus/internal/fetch"
+)
+
+// FetchObservation describes one completed fetch, for metrics.
+type FetchObservation struct {
+	ID       string
+	Duration time.Duration
+	Result   *fetch.Result
+	Err      error
+	TimedOut bool
+}
+
+// Scheduler refreshes services on their intervals without a terminal. It
+// polls the manager once a second, like the TUI's tick loop.
+type Scheduler struct {
+	manager *ServiceManager
+	client  *fetch.Client
+
+	// Timeout bounds each fetch.
+	Timeout time.Duration
+
+	// OnFetch, if set, is called after each fetch has been applied.
+	OnFetch func(FetchObservation)
+
+	wg sync.WaitGroup
+}
+
+func NewScheduler(sm *ServiceManager, client *fetch.Client) *Scheduler {
+	return &Scheduler{
+		manager: sm,
+		client:  client,
+		Timeout: 30 * time.Second,
+	}
+}
+
+// Run refreshes due services until ctx is cancelled, then waits for fetches
+// in flight to finish.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	s.refreshDue(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			s.wg.Wait()
+			return
+		case <-ticker.C:
+			s.refreshDue(ctx)
+		}
+	}
+}
+
+func (s *Scheduler) refreshDue(ctx context.Context) {
+	for _, id := range s.manager.Due(time.Now()) {
+		// Mark in flight before starting so the next tick doesn't fetch it again
+		s.manager.SetInFlight(id, true)
+		s.wg.Add(1)
+		go func(id string) {
+			defer s.wg.Done()
+			s.Refresh(ctx, id)
+		}(id)
+	}
+}
+
+// Refresh fetches one service immediately and records the result.
+func (s *Scheduler) Refresh(ctx context.Context, id string) error {
+	svc, ok := s.manager.Get(id)
+	if !ok {
+		return fmt.Errorf("service %s not found", id)
+	}
+	s.manager.SetInFlight(id, true)
+
+	fetchCtx, cancel := context.WithTimeout(ctx, s.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	result, err := s.client.Fetch(fetchCtx, fetchTarget(svc.Config))
+	if ctx.Err() != nil {
+		// Shutting down; don't record the aborted fetch as an outage
+		s.manager.SetInFlight(id, false)
+		return ctx.Err()
+	}
+	obs := FetchObservation{
+		ID:       id,
+		Duration: time.Since(start),
+		Result:   result,
+		Err:      err,
+		TimedOut: fetchCtx.Err() == context.DeadlineExceeded,
+	}
+
+	if err := s.manager.ApplyResult(id, result, err); err != nil {
+		return err
+	}
+
+	if s.OnFetch != nil {
+		s.OnFetch(obs)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// runServe implements `lazystatus serve`: refresh services on their
+// intervals without a terminal and expose the results over HTTP.
+func runServe(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	metricsAddr := fs.String("metrics", "", "address to serve Prometheus metrics on, e.g. :9090")
+	apiAddr := fs.String("api", "", "address to serve the JSON API on, e.g. 127.0.0.1:8080")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: lazystatus serve [flags]")
+		fmt.Fprintln(stderr, "")
+		fmt.Fprintln(stderr, "Refresh services in the background and serve their status over HTTP.")
+		fmt.Fprintln(stderr, "")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if *metricsAddr == "" && *apiAddr == "" {
+		fmt.Fprintln(stderr, "Error: nothing to serve; pass --metrics and/or --api")
+		fs.Usage()
+		return 2
+	}
+
+	sm, err := NewServiceManager()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error initializing service manager: %v\n", err)
+		return 1
+	}
+	defer sm.Close()
+
+	webhooks, err := NewWebhookNotifier(sm.Notifications().Webhooks)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error in notifications config: %v\n", err)
+		return 1
+	}
+	webhooks.OnError = func(err error) {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+	}
+
+	metrics := NewMetrics(sm)
+	scheduler := NewScheduler(sm, newFetchClient(sm.Settings()))
+	scheduler.OnFetch = func(obs FetchObservation) {
+		metrics.Observe(obs)
+		sm.Save()
+	}
+
+	// Metrics and the API share a listener when given the same address
+	muxes := make(map[string]*http.ServeMux)
+	muxFor := func(addr string) *http.ServeMux {
+		if muxes[addr] == nil {
+			muxes[addr] = http.NewServeMux()
+		}
+		return muxes[addr]
+	}
+	if *metricsAddr != "" {
+		muxFor(*metricsAddr).Handle("GET /metrics", metrics)
+	}
+	if *apiAddr != "" {
+		NewAPI(sm, scheduler).Register(muxFor(*apiAddr))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	events, unsubscribe := sm.Events().Subscribe(64)
+	defer unsubscribe()
+	go webhooks.Run(ctx, events)
+
+	hooks := NewHookRunner(sm)
+	hooks.OnResult = func(result HookResult) {
+		fmt.Fprint(stdout, formatHookResult(result))
+	}
+	hookEvents, unsubscribeHooks := sm.Events().Subscribe(64)
+	defer unsubscribeHooks()
+	hookCtx, cancelHooks := context.WithCancel(ctx)
+	hooksDone := make(chan struct{})
+	go func() {
+		hooks.Run(hookCtx, hookEvents)
+		close(hooksDone)
+	}()
+	defer func() {
+		cancelHooks()
+		<-hooksDone
+	}()
+
+	if err := serveHTTP(ctx, muxes, scheduler, stdout); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	sm.Save()
+	return 0
+}
+
+// formatHookResult renders an on_change result for the log, with its output
+// indented below.
+func formatHookResult(result HookResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "on_change %s: %s → %s: %s\n",
+		result.Event.ServiceName, result.Event.OldLevel.Key(), result.Event.NewLevel.Key(), result.Status())
+	for _, line := range strings.Split(strings.TrimRight(result.Output, "\n"), "\n") {
+		if line != "" {
+			fmt.Fprintf(&b, "  %s\n", line)
+		}
+	}
+	return b.String()
+}
+
+// serveHTTP runs the scheduler and one HTTP server per address until ctx is
+// cancelled or a server fails.
+func serveHTTP(ctx context.Context, handlers map[string]*http.ServeMux, scheduler *Scheduler, stdout io.Writer) error {
+	servers := make([]*http.Server, 0, len(handlers))
+	for addr, handler := range handlers {
+		servers = append(servers, &http.Server{
+			Addr:              addr,
+			Handler:           handler,
+			ReadHeaderTimeout: 10 * time.Second,
+			// Cancel requests on shutdown so event streams don't hold it up
+			BaseContext: func(net.Listener) context.Context { return ctx },
+		})
+	}
+
+	schedCtx, cancelSched := context.WithCancel(ctx)
+	schedDone := make(chan struct{})
+	go func() {
+		scheduler.Run(schedCtx)
+		close(schedDone)
+	}()
+
+	errCh := make(chan error, len(servers))
+	for _, srv := range servers {
+		go func(srv *http.Server) {
+			fmt.Fprintf(stdout, "lazystatus v%s serving on %s\n", version, srv.Addr)
+			errCh <- srv.ListenAndServe()
+		}(srv)
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-errCh:
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, srv := range servers {
+		srv.Shutdown(shutdownCtx)
+	}
+
+	cancelSched()
+	<-schedDone
+
+	if errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+	return err
+}
@@ -1,12 +1,17 @@
 package main
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/jakeasaurus/lazystatus/internal/fetch"
 )
 
 type StatusLevel int
@@ -40,6 +45,26 @@ func (s StatusLevel) String() string {
 	}
 }
 
+// Key is a stable, machine-readable name for the level.
+func (s StatusLevel) Key() string {
+	switch s {
+	case StatusOperational:
+		return "operational"
+	case StatusPlannedMaintenance:
+		return "planned_maintenance"
+	case StatusDegraded:
+		return "degraded"
+	case StatusMajorDisruption:
+		return "major_disruption"
+	case StatusConnectionError:
+		return "connection_error"
+	case StatusParseError:
+		return "parse_error"
+	default:
+		return "unknown"
+	}
+}
+
 func (s StatusLevel) Color() string {
 	switch s {
 	case StatusOperational:
@@ -48,13 +73,21 @@ func (s StatusLevel) Color() string {
 		return "#5FAFFF"
 	case StatusDegraded:
 		return "#FFAF5F"
-	case StatusMajorDisruption, StatusConnectionError, StatusParseError:
+	case StatusMajorDisruption:
 		return "#FF5F87"
+	case StatusConnectionError, StatusParseError:
+		return "#AF87FF"
 	default:
 		return "#626262"
 	}
 }
 
+// IsMonitoringProblem reports whether the level means lazystatus couldn't
+// read the status page, as opposed to the page reporting a problem.
+func (s StatusLevel) IsMonitoringProblem() bool {
+	return s == StatusConnectionError || s == StatusParseError
+}
+
 type IncidentUpdate struct {
 	Body      string    `json:"body"`
 	Status    string    `json:"status"`
@@ -62,14 +95,15 @@ type IncidentUpdate struct {
 }
 
 type Incident struct {
-	ID         string            `json:"id"`
-	Title      string            `json:"title"`
-	Status     string            `json:"status"`
-	Impact     string            `json:"impact"`
-	StartedAt  time.Time         `json:"started_at"`
-	UpdatedAt  time.Time         `json:"updated_at"`
-	ResolvedAt *time.Time        `json:"resolved_at,omitempty"`
-	Updates    []IncidentUpdate  `json:"updates,omitempty"`
+	ID         string           `json:"id"`
+	Title      string           `json:"title"`
+	URL        string           `json:"url,omitempty"`
+	Status     string           `json:"status"`
+	Impact     string           `json:"impact"`
+	StartedAt  time.Time        `json:"started_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+	ResolvedAt *time.Time       `json:"resolved_at,omitempty"`
+	Updates    []IncidentUpdate `json:"updates,omitempty"`
 }
 
 type Maintenance struct {
@@ -82,12 +116,92 @@ type Maintenance struct {
 	Updates   []IncidentUpdate  `json:"updates,omitempty"`
 }
 
+// Component is one part of a status page, e.g. "Actions" on GitHub.
+type Component struct {
+	Name   string      `json:"name"`
+	Status string      `json:"status"`
+	Group  string      `json:"group,omitempty"`
+	Level  StatusLevel `json:"level"`
+}
+
 type ServiceConfig struct {
-	Name                    string    `json:"name"`
-	URL                     string    `json:"url"`
-	RefreshIntervalSeconds  int       `json:"refresh_interval"`
-	LastChecked             time.Time `json:"last_checked,omitempty"`
-	CurrentStatus           string    `json:"current_status,omitempty"`
+	ID                     string           `json:"id"`
+	Name                   string           `json:"name"`
+	URL                    string           `json:"url"`
+	RefreshIntervalSeconds int              `json:"refresh_interval"`
+	LastChecked            time.Time        `json:"last_checked,omitempty"`
+	CurrentStatus          string           `json:"current_status,omitempty"`
+	Provider               string           `json:"provider,omitempty"`
+	Components             *ComponentFilter `json:"components,omitempty"`
+	Notify                 string           `json:"notify,omitempty"`
+	OnChange               string           `json:"on_change,omitempty"`
+	ConfirmChecks          int              `json:"confirm_checks,omitempty"`
+	// Products and Regions narrow pages that cover many of them, such as
+	// Google Cloud's, to the incidents that matter to this service.
+	Products []string `json:"products,omitempty"`
+	Regions  []string `json:"regions,omitempty"`
+	// Detected remembers which provider and endpoint worked, so detection
+	// doesn't run on every check. It is ignored when Provider is set.
+	Detected *DetectedSource `json:"detected,omitempty"`
+}
+
+// DetectedSource is the provider that last handled a service and the URL it
+// fetched.
+type DetectedSource struct {
+	Provider string `json:"provider"`
+	Endpoint string `json:"endpoint"`
+}
+
+// ComponentFilter selects which components of a page count towards the
+// service's status. Patterns match a component or group name, ignoring case,
+// and may use globs like "*us-east-1*".
+type ComponentFilter struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// UnmarshalJSON also accepts a plain list of names, which older configs used
+// to select components for display.
+func (f *ComponentFilter) UnmarshalJSON(data []byte) error {
+	var names []string
+	if err := json.Unmarshal(data, &names); err == nil {
+		*f = ComponentFilter{Include: names}
+		return nil
+	}
+
+	type plain ComponentFilter
+	return json.Unmarshal(data, (*plain)(f))
+}
+
+// ParseComponentFilter reads the comma-separated form used in the edit dialog,
+// where a leading "!" excludes a pattern. It returns nil for an empty filter.
+func ParseComponentFilter(s string) *ComponentFilter {
+	var f ComponentFilter
+	for _, pattern := range strings.Split(s, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if strings.HasPrefix(pattern, "!") {
+			if pattern = strings.TrimSpace(pattern[1:]); pattern != "" {
+				f.Exclude = append(f.Exclude, pattern)
+			}
+		} else if pattern != "" {
+			f.Include = append(f.Include, pattern)
+		}
+	}
+	if len(f.Include) == 0 && len(f.Exclude) == 0 {
+		return nil
+	}
+	return &f
+}
+
+func (f *ComponentFilter) String() string {
+	if f == nil {
+		return ""
+	}
+	patterns := append([]string{}, f.Include...)
+	for _, pattern := range f.Exclude {
+		patterns = append(patterns, "!"+pattern)
+	}
+	return strings.Join(patterns, ", ")
 }
 
 type ServiceState struct {
@@ -95,19 +209,85 @@ type ServiceState struct {
 	NextRefreshAt time.Time
 	InFlight      bool
 	StatusLevel   StatusLevel
+	Label         string
 	Incidents     []Incident
 	Maintenances  []Maintenance
+	Components    []Component
 	ParseNote     string
 	LastError     string
+
+	// PendingLevel has been seen on the last PendingCount checks but not
+	// enough of them to replace StatusLevel yet.
+	PendingLevel StatusLevel
+	PendingCount int
+	Flapping     bool
+	recent       []StatusLevel // levels seen on the last flapWindow checks
+	pageLevel    StatusLevel   // last level read from the page itself
+	// detectFailures counts consecutive failed checks through Detected.
+	detectFailures int
+}
+
+// OpenIncidents counts incidents that haven't been resolved.
+func (s ServiceState) OpenIncidents() int {
+	open := 0
+	for _, inc := range s.Incidents {
+		if inc.ResolvedAt == nil {
+			open++
+		}
+	}
+	return open
+}
+
+// ActiveMaintenances counts maintenance windows in progress at now.
+func (s ServiceState) ActiveMaintenances(now time.Time) int {
+	active := 0
+	for _, maint := range s.Maintenances {
+		if maint.Active(now) {
+			active++
+		}
+	}
+	return active
+}
+
+// Active reports whether the maintenance window is in progress at now.
+func (m Maintenance) Active(now time.Time) bool {
+	return m.Status == "in_progress" || (!now.Before(m.StartAt) && now.Before(m.EndAt))
 }
 
 type Settings struct {
 	DefaultRefreshInterval int `json:"default_refresh_interval"`
+	// ConfirmChecks is how many consecutive checks must see a new level
+	// before it replaces the current one.
+	ConfirmChecks int `json:"confirm_checks"`
+	// FlapThreshold is how many level changes within the last 10 checks
+	// mark a service as flapping; 0 disables flap detection.
+	FlapThreshold int `json:"flap_threshold"`
+	// RetryAttempts is how many times a request is tried before a check
+	// fails, including the first.
+	RetryAttempts int `json:"retry_attempts"`
+	// After CircuitBreakerThreshold consecutive failed checks against a
+	// host, or when it rate-limits us, it isn't requested again for
+	// CircuitBreakerCooldown seconds.
+	CircuitBreakerThreshold int `json:"circuit_breaker_threshold"`
+	CircuitBreakerCooldown  int `json:"circuit_breaker_cooldown"`
+	// MaxBodyKB is the largest response read from a status page, in KB.
+	MaxBodyKB int `json:"max_body_kb"`
+}
+
+type NotificationConfig struct {
+	Webhooks []WebhookConfig `json:"webhooks,omitempty"`
+	// OnChange is run for services that don't set their own on_change.
+	OnChange        string `json:"on_change,omitempty"`
+	OnChangeTimeout int    `json:"on_change_timeout,omitempty"`
+	// MonitoringProblems routes desktop notifications for failed checks:
+	// "notify" (default) or "off".
+	MonitoringProblems string `json:"monitoring_problems,omitempty"`
 }
 
 type Config struct {
-	Services []ServiceConfig `json:"services"`
-	Settings Settings        `json:"settings"`
+	Services      []ServiceConfig    `json:"services"`
+	Settings      Settings           `json:"settings"`
+	Notifications NotificationConfig `json:"notifications,omitzero"`
 }
 
 type ServiceManager struct {
@@ -115,9 +295,31 @@ type ServiceManager struct {
 	config   Config
 	states   []ServiceState
 	filePath string
+	history  *History
+	events   *EventBus
+	lock     *os.File // held while this process owns the store
+
+	// held are monitoring_failed events waiting to see whether we're
+	// offline, and unreported the services whose failure went into an
+	// offline event instead; see routeMonitoring.
+	held       []Event
+	unreported map[string]bool
 }
 
+// NewServiceManager loads the config and opens the status history for an
+// interactive or long-running session. Only one such session may own the
+// store at a time; another gets a *StoreLockedError.
 func NewServiceManager() (*ServiceManager, error) {
+	return newServiceManager(true)
+}
+
+// LoadServiceManager loads the config without the status history, for
+// one-shot commands that may run alongside the TUI.
+func LoadServiceManager() (*ServiceManager, error) {
+	return newServiceManager(false)
+}
+
+func newServiceManager(withHistory bool) (*ServiceManager, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get home directory: %w", err)
@@ -130,11 +332,35 @@ func NewServiceManager() (*ServiceManager, error) {
 
 	filePath := filepath.Join(configDir, "config.json")
 
+	var history *History
+	var lock *os.File
+	if withHistory {
+		lock, err = lockStore(configDir)
+		if err != nil {
+			return nil, err
+		}
+		history, err = OpenHistory(configDir)
+		if err != nil {
+			lock.Close()
+			return nil, fmt.Errorf("failed to open status history: %w", err)
+		}
+	}
+
 	sm := &ServiceManager{
-		filePath: filePath,
+		filePath:   filePath,
+		history:    history,
+		lock:       lock,
+		events:     NewEventBus(),
+		unreported: make(map[string]bool),
 		config: Config{
 			Settings: Settings{
-				DefaultRefreshInterval: 30,
+				DefaultRefreshInterval:  30,
+				ConfirmChecks:           1,
+				FlapThreshold:           4,
+				RetryAttempts:           fetch.DefaultRetryPolicy.MaxAttempts,
+				CircuitBreakerThreshold: fetch.DefaultBreakerThreshold,
+				CircuitBreakerCooldown:  int(fetch.DefaultBreakerCooldown / time.Second),
+				MaxBodyKB:               fetch.DefaultMaxBodySize / 1024,
 			},
 		},
 	}
@@ -145,10 +371,25 @@ func NewServiceManager() (*ServiceManager, error) {
 		}
 	}
 
-	sm.initStates()
+	if sm.initStates() {
+		if err := sm.Save(); err != nil {
+			return nil, fmt.Errorf("failed to save service IDs: %w", err)
+		}
+	}
+	sm.history.Retain(sm.serviceIDs())
 	return sm, nil
 }
 
+// serviceIDs returns the ID of every configured service. The caller must
+// hold sm.mu.
+func (sm *ServiceManager) serviceIDs() []string {
+	ids := make([]string, len(sm.config.Services))
+	for i, cfg := range sm.config.Services {
+		ids[i] = cfg.ID
+	}
+	return ids
+}
+
 func (sm *ServiceManager) Load() error {
 	data, err := os.ReadFile(sm.filePath)
 	if err != nil {
@@ -170,18 +411,50 @@ func (sm *ServiceManager) Save() error {
 	return os.WriteFile(sm.filePath, data, 0644)
 }
 
-func (sm *ServiceManager) initStates() {
+// initStates builds the runtime state for each configured service. It reports
+// whether any service was missing an ID and had one assigned.
+func (sm *ServiceManager) initStates() bool {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
+	var assigned bool
 	sm.states = make([]ServiceState, len(sm.config.Services))
 	for i, cfg := range sm.config.Services {
+		if cfg.ID == "" {
+			// Configs written before services had IDs keyed history by name
+			cfg.ID = newServiceID()
+			sm.history.Rekey(cfg.Name, cfg.ID)
+			sm.config.Services[i] = cfg
+			assigned = true
+		}
 		sm.states[i] = ServiceState{
 			Config:        cfg,
 			NextRefreshAt: time.Now(),
 			StatusLevel:   StatusUnknown,
 		}
 	}
+	return assigned
+}
+
+// newServiceID returns a random identifier that stays with a service for its
+// lifetime, regardless of renames or its position in the list.
+func newServiceID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// indexOf returns the position of the service with the given ID, or -1. The
+// caller must hold sm.mu.
+func (sm *ServiceManager) indexOf(id string) int {
+	for i, state := range sm.states {
+		if state.Config.ID == id {
+			return i
+		}
+	}
+	return -1
 }
 
 func (sm *ServiceManager) List() []ServiceState {
@@ -193,13 +466,28 @@ func (sm *ServiceManager) List() []ServiceState {
 	return result
 }
 
-func (sm *ServiceManager) Add(cfg ServiceConfig) error {
+func (sm *ServiceManager) Get(id string) (ServiceState, bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	index := sm.indexOf(id)
+	if index < 0 {
+		return ServiceState{}, false
+	}
+	return sm.states[index], true
+}
+
+// Add stores a new service and returns its ID.
+func (sm *ServiceManager) Add(cfg ServiceConfig) (string, error) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
 	if cfg.RefreshIntervalSeconds == 0 {
 		cfg.RefreshIntervalSeconds = sm.config.Settings.DefaultRefreshInterval
 	}
+	if cfg.ID == "" {
+		cfg.ID = newServiceID()
+	}
 
 	sm.config.Services = append(sm.config.Services, cfg)
 	sm.states = append(sm.states, ServiceState{
@@ -208,20 +496,28 @@ func (sm *ServiceManager) Add(cfg ServiceConfig) error {
 		StatusLevel:   StatusUnknown,
 	})
 
-	return nil
+	return cfg.ID, nil
 }
 
-func (sm *ServiceManager) Update(index int, cfg ServiceConfig) error {
+func (sm *ServiceManager) Update(id string, cfg ServiceConfig) error {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
-	if index < 0 || index >= len(sm.config.Services) {
-		return fmt.Errorf("index out of range")
+	index := sm.indexOf(id)
+	if index < 0 {
+		return fmt.Errorf("service %s not found", id)
 	}
 
 	if cfg.RefreshIntervalSeconds == 0 {
 		cfg.RefreshIntervalSeconds = sm.config.Settings.DefaultRefreshInterval
 	}
+	cfg.ID = id
+	// What was detected for the old URL may not suit the new one
+	old := sm.states[index].Config
+	if cfg.URL != old.URL || cfg.Provider != old.Provider {
+		cfg.Detected = nil
+		sm.states[index].detectFailures = 0
+	}
 
 	sm.config.Services[index] = cfg
 	sm.states[index].Config = cfg
@@ -229,70 +525,183 @@ func (sm *ServiceManager) Update(index int, cfg ServiceConfig) error {
 	return nil
 }
 
-func (sm *ServiceManager) Remove(index int) error {
+func (sm *ServiceManager) Remove(id string) error {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
-	if index < 0 || index >= len(sm.config.Services) {
-		return fmt.Errorf("index out of range")
+	index := sm.indexOf(id)
+	if index < 0 {
+		return fmt.Errorf("service %s not found", id)
 	}
 
 	sm.config.Services = append(sm.config.Services[:index], sm.config.Services[index+1:]...)
 	sm.states = append(sm.states[:index], sm.states[index+1:]...)
+	sm.dropHeld(id)
+	delete(sm.unreported, id)
+	sm.history.Retain(sm.serviceIDs())
 
 	return nil
 }
 
-func (sm *ServiceManager) SetInFlight(index int, inFlight bool) error {
+func (sm *ServiceManager) SetInFlight(id string, inFlight bool) error {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
-	if index < 0 || index >= len(sm.states) {
-		return fmt.Errorf("index out of range")
+	index := sm.indexOf(id)
+	if index < 0 {
+		return fmt.Errorf("service %s not found", id)
 	}
 
 	sm.states[index].InFlight = inFlight
 	return nil
 }
 
-func (sm *ServiceManager) UpdateStatus(index int, level StatusLevel, incidents []Incident, maintenances []Maintenance, parseNote, lastError string) error {
+func (sm *ServiceManager) UpdateStatus(id string, level StatusLevel, label string, incidents []Incident, maintenances []Maintenance, components []Component, parseNote, lastError string) error {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
-	if index < 0 || index >= len(sm.states) {
-		return fmt.Errorf("index out of range")
+	index := sm.indexOf(id)
+	if index < 0 {
+		return fmt.Errorf("service %s not found", id)
 	}
 
 	now := time.Now()
-	sm.states[index].StatusLevel = level
-	sm.states[index].Incidents = incidents
-	sm.states[index].Maintenances = maintenances
-	sm.states[index].ParseNote = parseNote
-	sm.states[index].LastError = lastError
-	sm.states[index].InFlight = false
-	sm.states[index].Config.LastChecked = now
-	sm.states[index].Config.CurrentStatus = level.String()
-	
-	interval := time.Duration(sm.states[index].Config.RefreshIntervalSeconds) * time.Second
-	sm.states[index].NextRefreshAt = now.Add(interval)
-
+	wasOffline := sm.offline()
+	prev := sm.states[index]
+	state := &sm.states[index]
+	state.InFlight = false
+	state.Config.LastChecked = now
 	sm.config.Services[index].LastChecked = now
-	sm.config.Services[index].CurrentStatus = level.String()
+	interval := time.Duration(state.Config.RefreshIntervalSeconds) * time.Second
+	state.NextRefreshAt = now.Add(interval)
+
+	var events []Event
+	switch {
+	case state.observe(level, sm.confirmChecks(state.Config), sm.config.Settings.FlapThreshold):
+		// Transitions of a flapping service are reported as flapping instead
+		if !state.Flapping {
+			events = statusEvents(prev, level, incidents, maintenances, now)
+		}
+
+		state.StatusLevel = level
+		state.Label = label
+		state.Incidents = incidents
+		state.Maintenances = maintenances
+		state.Components = components
+		state.ParseNote = parseNote
+		state.LastError = lastError
+		state.Config.CurrentStatus = level.String()
+		sm.config.Services[index].CurrentStatus = level.String()
+		if hasPageData(level) {
+			state.pageLevel = level
+		}
+
+		sm.history.Record(id, level, now)
+	case hasPageData(level):
+		// The level waits for confirmation, but what the page lists is
+		// current; diffing against the committed level reports incidents
+		// without a status change
+		if !state.Flapping {
+			events = statusEvents(prev, prev.StatusLevel, incidents, maintenances, now)
+		}
+		state.Incidents = incidents
+		state.Maintenances = maintenances
+		state.Components = components
+		state.ParseNote = parseNote
+	}
+	events = append(flapEvents(prev, *state, now), events...)
+	sm.events.publish(sm.routeMonitoring(id, level, wasOffline, events, now))
 
 	return nil
 }
 
-func (sm *ServiceManager) GetNextRefreshAt(index int) (time.Time, error) {
+func (sm *ServiceManager) GetNextRefreshAt(id string) (time.Time, error) {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
 
-	if index < 0 || index >= len(sm.states) {
-		return time.Time{}, fmt.Errorf("index out of range")
+	index := sm.indexOf(id)
+	if index < 0 {
+		return time.Time{}, fmt.Errorf("service %s not found", id)
 	}
 
 	return sm.states[index].NextRefreshAt, nil
 }
 
+// Due returns the IDs of services whose next refresh time has passed and that
+// aren't already being fetched.
+func (sm *ServiceManager) Due(now time.Time) []string {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	var ids []string
+	for _, state := range sm.states {
+		if !state.InFlight && now.After(state.NextRefreshAt) {
+			ids = append(ids, state.Config.ID)
+		}
+	}
+	return ids
+}
+
+// Events returns the bus that UpdateStatus publishes transitions on.
+func (sm *ServiceManager) Events() *EventBus {
+	return sm.events
+}
+
+func (sm *ServiceManager) History() *History {
+	return sm.history
+}
+
+// Close marks every service as unknown in the history, so time while
+// lazystatus isn't running doesn't count towards uptime, closes it and
+// releases the store.
+func (sm *ServiceManager) Close() error {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	now := time.Now()
+	for _, state := range sm.states {
+		sm.history.Record(state.Config.ID, StatusUnknown, now)
+	}
+	err := sm.history.Close()
+	if sm.lock != nil {
+		sm.lock.Close()
+	}
+	return err
+}
+
+func (sm *ServiceManager) Notifications() NotificationConfig {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.config.Notifications
+}
+
+// Offline reports whether every service failed its last check, which points
+// at our own connectivity rather than every vendor being down at once.
+func (sm *ServiceManager) Offline() bool {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.offline()
+}
+
+// offline is Offline for callers that hold sm.mu.
+func (sm *ServiceManager) offline() bool {
+	if len(sm.states) < 2 {
+		return false
+	}
+	for _, state := range sm.states {
+		if !state.StatusLevel.IsMonitoringProblem() {
+			return false
+		}
+	}
+	return true
+}
+
+func (sm *ServiceManager) Settings() Settings {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.config.Settings
+}
+
 func (sm *ServiceManager) GetDefaultInterval() int {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// WebhookConfig describes one outbound webhook. Body is a text/template
+// rendered with a WebhookPayload; when empty the event is sent as JSON.
+type WebhookConfig struct {
+	Name    string            `json:"name,omitempty"`
+	URL     string            `json:"url"`
+	Method  string            `json:"method,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	// Events limits the webhook to these event types; empty means all.
+	Events []EventType `json:"events,omitempty"`
+}
+
+// WebhookPayload is the data a webhook body template is rendered with.
+type WebhookPayload struct {
+	Event     EventType
+	ServiceID string
+	Service   string
+	URL       string
+	OldLevel  string
+	NewLevel  string
+	Title     string // incident or maintenance title, if any
+	Summary   string
+	At        time.Time
+	// Raw is the underlying event, for fields not flattened above.
+	Raw Event
+}
+
+var webhookFuncs = template.FuncMap{
+	// json encodes a value for embedding in a JSON body, e.g.
+	// {"text": {{json .Summary}}}
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+type webhook struct {
+	config WebhookConfig
+	body   *template.Template
+}
+
+// WebhookNotifier posts events from the bus to the configured webhooks.
+type WebhookNotifier struct {
+	hooks  []webhook
+	client *http.Client
+
+	// OnError, if set, is called when a webhook can't be delivered.
+	OnError func(error)
+}
+
+// NewWebhookNotifier validates the webhooks and parses their templates.
+func NewWebhookNotifier(configs []WebhookConfig) (*WebhookNotifier, error) {
+	n := &WebhookNotifier{client: &http.Client{Timeout: webhookTimeout}}
+	for i, cfg := range configs {
+		name := webhookName(cfg, i)
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("webhook %s: url is required", name)
+		}
+		hook := webhook{config: cfg}
+		if cfg.Body != "" {
+			tmpl, err := template.New(name).Funcs(webhookFuncs).Parse(cfg.Body)
+			if err != nil {
+				return nil, fmt.Errorf("webhook %s: %w", name, err)
+			}
+			hook.body = tmpl
+		}
+		n.hooks = append(n.hooks, hook)
+	}
+	return n, nil
+}
+
+func webhookName(cfg WebhookConfig, index int) string {
+	if cfg.Name != "" {
+		return cfg.Name
+	}
+	return fmt.Sprintf("#%d", index+1)
+}
+
+// Run delivers events until ctx is cancelled or the channel is closed.
+func (n *WebhookNotifier) Run(ctx context.Context, events <-chan Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := n.Send(ctx, event); err != nil && n.OnError != nil {
+				n.OnError(err)
+			}
+		}
+	}
+}
+
+// Send delivers one event to every webhook that wants it.
+func (n *WebhookNotifier) Send(ctx context.Context, event Event) error {
+	var errs []error
+	for i, hook := range n.hooks {
+		if !hook.wants(event.Type) {
+			continue
+		}
+		if err := n.post(ctx, hook, event); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", webhookName(hook.config, i), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h webhook) wants(eventType EventType) bool {
+	if len(h.config.Events) == 0 {
+		return true
+	}
+	for _, t := range h.config.Events {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *WebhookNotifier) post(ctx context.Context, hook webhook, event Event) error {
+	var body bytes.Buffer
+	if hook.body != nil {
+		if err := hook.body.Execute(&body, newWebhookPayload(event)); err != nil {
+			return err
+		}
+	} else if err := json.NewEncoder(&body).Encode(event); err != nil {
+		return err
+	}
+
+	method := strings.ToUpper(hook.config.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequestWithContext(ctx, method, hook.config.URL, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "lazystatus/"+version)
+	for k, v := range hook.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func newWebhookPayload(event Event) WebhookPayload {
+	payload := WebhookPayload{
+		Event:     event.Type,
+		ServiceID: event.ServiceID,
+		Service:   event.ServiceName,
+		URL:       event.URL,
+		OldLevel:  event.OldLevel.String(),
+		NewLevel:  event.NewLevel.String(),
+		At:        event.At,
+		Raw:       event,
+	}
+
+	switch {
+	case event.Incident != nil:
+		payload.Title = event.Incident.Title
+	case event.Maintenance != nil:
+		payload.Title = event.Maintenance.Title
+	}
+
+	switch event.Type {
+	case EventStatusChanged:
+		payload.Summary = fmt.Sprintf("%s: %s → %s", payload.Service, payload.OldLevel, payload.NewLevel)
+	case EventIncidentOpened:
+		payload.Summary = fmt.Sprintf("%s: incident opened: %s", payload.Service, payload.Title)
+	case EventIncidentResolved:
+		payload.Summary = fmt.Sprintf("%s: incident resolved: %s", payload.Service, payload.Title)
+	case EventMaintenanceStarted:
+		payload.Summary = fmt.Sprintf("%s: maintenance started: %s", payload.Service, payload.Title)
+	case EventMaintenanceEnded:
+		payload.Summary = fmt.Sprintf("%s: maintenance ended: %s", payload.Service, payload.Title)
+	case EventFlappingStarted:
+		payload.Summary = fmt.Sprintf("%s: flapping between levels", payload.Service)
+	case EventFlappingStopped:
+		payload.Summary = fmt.Sprintf("%s: stopped flapping, now %s", payload.Service, payload.NewLevel)
+	case EventMonitoringFailed:
+		payload.Summary = fmt.Sprintf("%s: can't check status page (%s)", payload.Service, payload.NewLevel)
+	case EventMonitoringRecovered:
+		payload.Summary = fmt.Sprintf("%s: status page reachable again, %s", payload.Service, payload.NewLevel)
+	case EventOffline:
+		payload.Summary = "lazystatus: can't reach any status pages; check the network or VPN"
+	case EventOnline:
+		payload.Summary = "lazystatus: status pages reachable again"
+	}
+	return payload
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// receivedRequest is what the test receiver saw of one webhook delivery.
+type receivedRequest struct {
+	Method string
+	Header http.Header
+	Body   string
+}
+
+// newReceiver starts a local webhook receiver that records every request and
+// replies with status.
+func newReceiver(t *testing.T, status int) (*httptest.Server, <-chan receivedRequest) {
+	t.Helper()
+	received := make(chan receivedRequest, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- receivedRequest{Method: r.Method, Header: r.Header, Body: string(body)}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, received
+}
+
+func testEvent(eventType EventType) Event {
+	return Event{
+		Type:        eventType,
+		ServiceID:   "a1b2c3d4e5f60718",
+		ServiceName: "GitHub",
+		URL:         "https://www.githubstatus.com",
+		At:          time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC),
+		OldLevel:    StatusOperational,
+		NewLevel:    StatusDegraded,
+	}
+}
+
+func TestWebhookTemplatedBody(t *testing.T) {
+	srv, received := newReceiver(t, http.StatusOK)
+	n, err := NewWebhookNotifier([]WebhookConfig{{
+		Name:    "slack",
+		URL:     srv.URL,
+		Method:  "put",
+		Headers: map[string]string{"Authorization": "Bearer s3cret"},
+		Body:    `{"text": {{json .Summary}}, "service": {{json .ServiceID}}}`,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := n.Send(context.Background(), testEvent(EventStatusChanged)); err != nil {
+		t.Fatal(err)
+	}
+
+	req := <-received
+	if req.Method != http.MethodPut {
+		t.Errorf("got method %s, want PUT", req.Method)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer s3cret" {
+		t.Errorf("got Authorization %q", got)
+	}
+	if got := req.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("got Content-Type %q", got)
+	}
+	var body map[string]string
+	if err := json.Unmarshal([]byte(req.Body), &body); err != nil {
+		t.Fatalf("body %q is not JSON: %v", req.Body, err)
+	}
+	if want := "GitHub: Operational → Degraded Performance"; body["text"] != want {
+		t.Errorf("got text %q, want %q", body["text"], want)
+	}
+	if body["service"] != "a1b2c3d4e5f60718" {
+		t.Errorf("got service %q", body["service"])
+	}
+}
+
+func TestWebhookDefaultBody(t *testing.T) {
+	srv, received := newReceiver(t, http.StatusNoContent)
+	n, err := NewWebhookNotifier([]WebhookConfig{{URL: srv.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := testEvent(EventIncidentOpened)
+	event.Incident = &Incident{ID: "r5w4mkpd2ns2", Title: "Elevated error rates"}
+	if err := n.Send(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+
+	req := <-received
+	if req.Method != http.MethodPost {
+		t.Errorf("got method %s, want POST", req.Method)
+	}
+	var body struct {
+		Type     string `json:"type"`
+		OldLevel string `json:"old_level"`
+		NewLevel string `json:"new_level"`
+		Incident struct {
+			Title string `json:"title"`
+		} `json:"incident"`
+	}
+	if err := json.Unmarshal([]byte(req.Body), &body); err != nil {
+		t.Fatalf("body %q is not JSON: %v", req.Body, err)
+	}
+	if body.Type != "incident_opened" || body.OldLevel != "operational" || body.NewLevel != "degraded" ||
+		body.Incident.Title != "Elevated error rates" {
+		t.Errorf("got body %s", req.Body)
+	}
+}
+
+func TestWebhookEventFilter(t *testing.T) {
+	srv, received := newReceiver(t, http.StatusOK)
+	n, err := NewWebhookNotifier([]WebhookConfig{{
+		URL:    srv.URL,
+		Events: []EventType{EventIncidentOpened, EventIncidentResolved},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, eventType := range []EventType{EventStatusChanged, EventIncidentResolved, EventMaintenanceStarted} {
+		if err := n.Send(context.Background(), testEvent(eventType)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := len(received); got != 1 {
+		t.Fatalf("got %d requests, want 1", got)
+	}
+	if req := <-received; !strings.Contains(req.Body, `"incident_resolved"`) {
+		t.Errorf("got body %s", req.Body)
+	}
+}
+
+func TestWebhookErrors(t *testing.T) {
+	srv, _ := newReceiver(t, http.StatusInternalServerError)
+	n, err := NewWebhookNotifier([]WebhookConfig{{Name: "ops", URL: srv.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = n.Send(context.Background(), testEvent(EventStatusChanged))
+	if err == nil || !strings.Contains(err.Error(), "webhook ops: HTTP 500") {
+		t.Errorf("got %v, want an HTTP 500 error", err)
+	}
+
+	if _, err := NewWebhookNotifier([]WebhookConfig{{Name: "bad", URL: srv.URL, Body: "{{.Nope"}}); err == nil {
+		t.Error("got nil error for an unparsable template")
+	}
+	if _, err := NewWebhookNotifier([]WebhookConfig{{Name: "nourl"}}); err == nil {
+		t.Error("got nil error for a webhook without a url")
+	}
+}